@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"main/token"
+	"math/big"
 	"strings"
 )
 
@@ -21,6 +22,13 @@ type Expression interface {
 	expressionNode()
 }
 
+// Pattern es lo que se puede ligar con enchanted o como parametro: un nombre
+// o un patron de destructuring.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 type Program struct {
 	Statements []Statement
 }
@@ -82,7 +90,7 @@ func (bs *BlockStatement) String() string {
 
 type FunctionLiteral struct {
 	Token      token.Token // The 'fn' token
-	Parameters []*Variable
+	Parameters []Pattern
 	Body       *BlockStatement
 }
 
@@ -134,9 +142,18 @@ func (b *Boolean) expressionNode()      {}
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
 func (b *Boolean) String() string       { return b.Token.Literal }
 
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (n *NullLiteral) expressionNode()      {}
+func (n *NullLiteral) TokenLiteral() string { return n.Token.Literal }
+func (n *NullLiteral) String() string       { return n.Token.Literal }
+
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int // Solo si el literal no cabe en un int64; Value queda en 0
 }
 
 func (il *IntegerLiteral) expressionNode()      {}
@@ -191,9 +208,10 @@ func (oe *InfixExpression) String() string {
 // ------------------------- Declaracion de variables --------------------------------
 
 type LetStatement struct {
-	Token token.Token // Incluye enchanted
-	Name  *Variable
-	Value Expression
+	Token   token.Token // Incluye enchanted
+	Name    *Variable
+	Pattern Pattern // Solo en destructuring, cuando Name es nil
+	Value   Expression
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -202,19 +220,35 @@ func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
 type Variable struct {
 	Token token.Token // Para el actual id, era Identifier
 	Value string
+
+	// Los llena el resolver. Depth es cuantos scopes de funcion hay que
+	// subir hasta el que declara la variable y Slot su indice en ese scope.
+	// Sin Resolved el evaluador busca el nombre en el Environment.
+	Resolved bool
+	Depth    int
+	Slot     int
 }
 
 func (i *Variable) String() string { return i.Value }
 
 func (i *Variable) expressionNode()      {}
+func (i *Variable) patternNode()         {}
 func (i *Variable) TokenLiteral() string { return i.Token.Literal }
 
+// Target devuelve lo que liga el enchanted: el nombre o el patron.
+func (ls *LetStatement) Target() Pattern {
+	if ls.Pattern != nil {
+		return ls.Pattern
+	}
+	return ls.Name
+}
+
 // Imprimir arbols como expresion
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	out.WriteString(ls.Target().String())
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -296,9 +330,10 @@ func (al *ArrayLiteral) String() string {
 }
 
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool // h?[k]: devuelve null si h es null
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -307,6 +342,9 @@ func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
 	out.WriteString("])")
@@ -314,9 +352,15 @@ func (ie *IndexExpression) String() string {
 }
 
 // ------------------------Hash Map--------------------------------------
+// HashPair es una entrada `llave: valor` de un HashLiteral.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
 type HashLiteral struct {
 	Token token.Token // the '{' token
-	Pairs map[Expression]Expression
+	Pairs []HashPair  // En el orden del codigo fuente
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -324,11 +368,115 @@ func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs, ", "))
 	out.WriteString("}")
 	return out.String()
 }
+
+// ------------------------Modulos--------------------------------------
+
+type ImportExpression struct {
+	Token token.Token // El token 'feat'
+	Path  string
+}
+
+func (ie *ImportExpression) expressionNode()      {}
+func (ie *ImportExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *ImportExpression) String() string {
+	return ie.TokenLiteral() + " \"" + ie.Path + "\""
+}
+
+// ------------------------Macros--------------------------------------
+
+type MacroLiteral struct {
+	Token      token.Token // El token 'folklore'
+	Parameters []*Variable
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+func (ml *MacroLiteral) String() string {
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+	out.WriteString(ml.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	out.WriteString(ml.Body.String())
+	return out.String()
+}
+
+// ------------------------Destructuring--------------------------------------
+
+// ArrayPattern es `[a, b, ...rest]`.
+type ArrayPattern struct {
+	Token    token.Token // El token '['
+	Elements []Pattern
+	Rest     *Variable // nil si no hay `...rest`
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// HashPattern es `{name, age}`: cada nombre toma el valor de la llave string
+// del mismo nombre.
+type HashPattern struct {
+	Token token.Token // El token '{'
+	Keys  []*Variable
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+	keys := []string{}
+	for _, k := range hp.Keys {
+		keys = append(keys, k.String())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(keys, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// PatternVariables devuelve los nombres que liga un patron, en orden.
+func PatternVariables(pattern Pattern) []*Variable {
+	switch p := pattern.(type) {
+	case *Variable:
+		return []*Variable{p}
+	case *ArrayPattern:
+		variables := []*Variable{}
+		for _, e := range p.Elements {
+			variables = append(variables, PatternVariables(e)...)
+		}
+		if p.Rest != nil {
+			variables = append(variables, p.Rest)
+		}
+		return variables
+	case *HashPattern:
+		return p.Keys
+	}
+	return nil
+}
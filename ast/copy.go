@@ -0,0 +1,169 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Copy devuelve una copia profunda de node: la copia no comparte nodos ni
+// slices con el original, asi que se puede modificar con Modify sin tocarlo.
+// Los tokens y lo que anoto el resolver se copian tal cual. Igual que
+// Children, conoce todos los tipos de nodo; con uno desconocido devuelve un
+// error.
+func Copy(node Node) (Node, error) {
+	c := &copier{}
+	copied := c.copy(node)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return copied, nil
+}
+
+// copier guarda el primer error de la copia para no cortar la recursion en
+// cada campo.
+type copier struct {
+	err error
+}
+
+func (c *copier) copy(node Node) Node {
+	if isNil(node) {
+		return node
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		cp := *n
+		cp.Statements = copyAll(c, n.Statements)
+		return &cp
+
+	// Statements
+	case *LetStatement:
+		cp := *n
+		cp.Name = copyOf(c, n.Name)
+		cp.Pattern = copyOf(c, n.Pattern)
+		cp.Value = copyOf(c, n.Value)
+		return &cp
+	case *ReturnStatement:
+		cp := *n
+		cp.ReturnValue = copyOf(c, n.ReturnValue)
+		return &cp
+	case *ExpressionStatement:
+		cp := *n
+		cp.Expression = copyOf(c, n.Expression)
+		return &cp
+	case *BlockStatement:
+		cp := *n
+		cp.Statements = copyAll(c, n.Statements)
+		return &cp
+
+	// Expresiones
+	case *IfExpression:
+		cp := *n
+		cp.Condition = copyOf(c, n.Condition)
+		cp.Consequence = copyOf(c, n.Consequence)
+		cp.Alternative = copyOf(c, n.Alternative)
+		return &cp
+	case *FunctionLiteral:
+		cp := *n
+		cp.Parameters = copyAll(c, n.Parameters)
+		cp.Body = copyOf(c, n.Body)
+		return &cp
+	case *MacroLiteral:
+		cp := *n
+		cp.Parameters = copyAll(c, n.Parameters)
+		cp.Body = copyOf(c, n.Body)
+		return &cp
+	case *CallExpression:
+		cp := *n
+		cp.Function = copyOf(c, n.Function)
+		cp.Arguments = copyAll(c, n.Arguments)
+		return &cp
+	case *PrefixExpression:
+		cp := *n
+		cp.Right = copyOf(c, n.Right)
+		return &cp
+	case *InfixExpression:
+		cp := *n
+		cp.Left = copyOf(c, n.Left)
+		cp.Right = copyOf(c, n.Right)
+		return &cp
+	case *IndexExpression:
+		cp := *n
+		cp.Left = copyOf(c, n.Left)
+		cp.Index = copyOf(c, n.Index)
+		return &cp
+	case *ArrayLiteral:
+		cp := *n
+		cp.Elements = copyAll(c, n.Elements)
+		return &cp
+	case *HashLiteral:
+		cp := *n
+		if n.Pairs != nil {
+			cp.Pairs = make([]HashPair, len(n.Pairs))
+			for i, pair := range n.Pairs {
+				cp.Pairs[i] = HashPair{Key: copyOf(c, pair.Key), Value: copyOf(c, pair.Value)}
+			}
+		}
+		return &cp
+
+	// Patrones
+	case *ArrayPattern:
+		cp := *n
+		cp.Elements = copyAll(c, n.Elements)
+		cp.Rest = copyOf(c, n.Rest)
+		return &cp
+	case *HashPattern:
+		cp := *n
+		cp.Keys = copyAll(c, n.Keys)
+		return &cp
+
+	// Hojas
+	case *Variable:
+		cp := *n
+		return &cp
+	case *IntegerLiteral:
+		cp := *n
+		if n.Big != nil {
+			cp.Big = new(big.Int).Set(n.Big)
+		}
+		return &cp
+	case *FloatLiteral:
+		cp := *n
+		return &cp
+	case *StringLiteral:
+		cp := *n
+		return &cp
+	case *Boolean:
+		cp := *n
+		return &cp
+	case *NullLiteral:
+		cp := *n
+		return &cp
+	case *ImportExpression:
+		cp := *n
+		return &cp
+
+	default:
+		if c.err == nil {
+			c.err = fmt.Errorf("ast.Copy: tipo de nodo inesperado %T", n)
+		}
+		return node
+	}
+}
+
+// copyOf copia un campo conservando su tipo; un campo nil queda nil.
+func copyOf[T Node](c *copier, node T) T {
+	copied, _ := c.copy(node).(T)
+	return copied
+}
+
+func copyAll[T Node](c *copier, nodes []T) []T {
+	if nodes == nil {
+		return nil
+	}
+	copied := make([]T, len(nodes))
+	for i, node := range nodes {
+		copied[i] = copyOf(c, node)
+	}
+	return copied
+}
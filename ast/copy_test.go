@@ -0,0 +1,55 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCopyIsDeep(t *testing.T) {
+	program := allNodes()
+	copied, err := Copy(program)
+	if err != nil {
+		t.Fatalf("Copy fallo: %v", err)
+	}
+	if !reflect.DeepEqual(program, copied) {
+		t.Fatalf("la copia es distinta del original.\nobtuvo=%s\nesperaba=%s", copied, program)
+	}
+
+	original := map[Node]bool{}
+	Inspect(program, func(n Node) bool {
+		if n != nil {
+			original[n] = true
+		}
+		return true
+	})
+	Inspect(copied, func(n Node) bool {
+		if original[n] {
+			t.Errorf("la copia comparte el nodo %s", nodeName(n))
+		}
+		return true
+	})
+
+	Modify(copied, func(n Node) Node {
+		if v, ok := n.(*Variable); ok {
+			v.Value = "_" + v.Value
+		}
+		return n
+	})
+	if !reflect.DeepEqual(program, allNodes()) {
+		t.Errorf("modificar la copia cambio el original: %s", program)
+	}
+}
+
+func TestCopyUnknownNodeType(t *testing.T) {
+	program := &Program{Statements: []Statement{
+		&ExpressionStatement{Expression: &IntegerLiteral{Value: 1}},
+	}}
+	program.Statements[0].(*ExpressionStatement).Expression = nil
+	if _, err := Copy(program); err != nil {
+		t.Fatalf("un hijo nil no deberia ser un error: %v", err)
+	}
+
+	if _, err := Copy(&unknownNode{}); err == nil {
+		t.Errorf("Copy deberia devolver un error con un nodo desconocido")
+	}
+}
@@ -0,0 +1,428 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"main/token"
+	"math/big"
+)
+
+// jsonNode es la forma serializada de cualquier nodo. Kind indica el tipo y
+// solo se llenan los campos que ese tipo usa.
+type jsonNode struct {
+	Kind  string     `json:"kind"`
+	Token *jsonToken `json:"token,omitempty"`
+
+	// Value es el valor de un literal o la expresion de un LetStatement.
+	Value    json.RawMessage `json:"value,omitempty"`
+	Operator string          `json:"operator,omitempty"`
+	Path     string          `json:"path,omitempty"`
+	Optional bool            `json:"optional,omitempty"`
+
+	// Resolucion de un Variable, ver ast.Variable.
+	Resolved bool `json:"resolved,omitempty"`
+	Depth    int  `json:"depth,omitempty"`
+	Slot     int  `json:"slot,omitempty"`
+
+	Name        *jsonNode `json:"name,omitempty"`
+	Pattern     *jsonNode `json:"pattern,omitempty"`
+	Expression  *jsonNode `json:"expression,omitempty"`
+	ReturnValue *jsonNode `json:"returnValue,omitempty"`
+	Condition   *jsonNode `json:"condition,omitempty"`
+	Consequence *jsonNode `json:"consequence,omitempty"`
+	Alternative *jsonNode `json:"alternative,omitempty"`
+	Function    *jsonNode `json:"function,omitempty"`
+	Body        *jsonNode `json:"body,omitempty"`
+	Left        *jsonNode `json:"left,omitempty"`
+	Right       *jsonNode `json:"right,omitempty"`
+	Index       *jsonNode `json:"index,omitempty"`
+	Rest        *jsonNode `json:"rest,omitempty"`
+
+	Statements []*jsonNode `json:"statements,omitempty"`
+	Parameters []*jsonNode `json:"parameters,omitempty"`
+	Arguments  []*jsonNode `json:"arguments,omitempty"`
+	Elements   []*jsonNode `json:"elements,omitempty"`
+	Keys       []*jsonNode `json:"keys,omitempty"`
+	Pairs      []jsonPair  `json:"pairs,omitempty"`
+}
+
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Literal string          `json:"literal"`
+	Line    int             `json:"line"`
+	Column  int             `json:"column"`
+}
+
+type jsonPair struct {
+	Key   *jsonNode `json:"key"`
+	Value *jsonNode `json:"value"`
+}
+
+// MarshalProgram codifica el programa completo en JSON, incluyendo los
+// tokens y sus posiciones, de forma que UnmarshalProgram lo reconstruya igual.
+func MarshalProgram(program *Program) ([]byte, error) {
+	node, err := encodeNode(program)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(node)
+}
+
+// MarshalProgramIndent es MarshalProgram con indentacion, para leerlo.
+func MarshalProgramIndent(program *Program) ([]byte, error) {
+	node, err := encodeNode(program)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(node, "", "  ")
+}
+
+func UnmarshalProgram(data []byte) (*Program, error) {
+	var node jsonNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	decoded, err := decodeNode(&node)
+	if err != nil {
+		return nil, err
+	}
+	program, ok := decoded.(*Program)
+	if !ok {
+		return nil, fmt.Errorf("se esperaba un Program, se obtuvo: %s", node.Kind)
+	}
+	return program, nil
+}
+
+// ---------------------------Codificar--------------------------------
+
+func encodeToken(t token.Token) *jsonToken {
+	return &jsonToken{Type: t.Type, Literal: t.Literal, Line: t.Line, Column: t.Column}
+}
+
+func encodeValue(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func encodeNode(node Node) (*jsonNode, error) {
+	if isNil(node) {
+		return nil, nil
+	}
+
+	var err error
+	one := func(n Node) *jsonNode {
+		if err != nil {
+			return nil
+		}
+		var encoded *jsonNode
+		encoded, err = encodeNode(n)
+		return encoded
+	}
+	list := func(n int, at func(int) Node) []*jsonNode {
+		nodes := make([]*jsonNode, n)
+		for i := range nodes {
+			nodes[i] = one(at(i))
+		}
+		return nodes
+	}
+
+	var out *jsonNode
+	switch n := node.(type) {
+	case *Program:
+		out = &jsonNode{Kind: "Program",
+			Statements: list(len(n.Statements), func(i int) Node { return n.Statements[i] })}
+
+	case *LetStatement:
+		out = &jsonNode{Kind: "LetStatement", Token: encodeToken(n.Token),
+			Name: one(n.Name), Pattern: one(n.Pattern)}
+		if value := one(n.Value); value != nil {
+			out.Value = encodeValue(value)
+		}
+	case *ReturnStatement:
+		out = &jsonNode{Kind: "ReturnStatement", Token: encodeToken(n.Token),
+			ReturnValue: one(n.ReturnValue)}
+	case *ExpressionStatement:
+		out = &jsonNode{Kind: "ExpressionStatement", Token: encodeToken(n.Token),
+			Expression: one(n.Expression)}
+	case *BlockStatement:
+		out = &jsonNode{Kind: "BlockStatement", Token: encodeToken(n.Token),
+			Statements: list(len(n.Statements), func(i int) Node { return n.Statements[i] })}
+
+	case *IfExpression:
+		out = &jsonNode{Kind: "IfExpression", Token: encodeToken(n.Token),
+			Condition: one(n.Condition), Consequence: one(n.Consequence), Alternative: one(n.Alternative)}
+	case *FunctionLiteral:
+		out = &jsonNode{Kind: "FunctionLiteral", Token: encodeToken(n.Token),
+			Parameters: list(len(n.Parameters), func(i int) Node { return n.Parameters[i] }),
+			Body:       one(n.Body)}
+	case *MacroLiteral:
+		out = &jsonNode{Kind: "MacroLiteral", Token: encodeToken(n.Token),
+			Parameters: list(len(n.Parameters), func(i int) Node { return n.Parameters[i] }),
+			Body:       one(n.Body)}
+	case *CallExpression:
+		out = &jsonNode{Kind: "CallExpression", Token: encodeToken(n.Token),
+			Function:  one(n.Function),
+			Arguments: list(len(n.Arguments), func(i int) Node { return n.Arguments[i] })}
+	case *PrefixExpression:
+		out = &jsonNode{Kind: "PrefixExpression", Token: encodeToken(n.Token),
+			Operator: n.Operator, Right: one(n.Right)}
+	case *InfixExpression:
+		out = &jsonNode{Kind: "InfixExpression", Token: encodeToken(n.Token),
+			Operator: n.Operator, Left: one(n.Left), Right: one(n.Right)}
+	case *IndexExpression:
+		out = &jsonNode{Kind: "IndexExpression", Token: encodeToken(n.Token),
+			Left: one(n.Left), Index: one(n.Index), Optional: n.Optional}
+	case *ArrayLiteral:
+		out = &jsonNode{Kind: "ArrayLiteral", Token: encodeToken(n.Token),
+			Elements: list(len(n.Elements), func(i int) Node { return n.Elements[i] })}
+	case *HashLiteral:
+		out = &jsonNode{Kind: "HashLiteral", Token: encodeToken(n.Token), Pairs: []jsonPair{}}
+		for _, pair := range n.Pairs {
+			out.Pairs = append(out.Pairs, jsonPair{Key: one(pair.Key), Value: one(pair.Value)})
+		}
+	case *ImportExpression:
+		out = &jsonNode{Kind: "ImportExpression", Token: encodeToken(n.Token), Path: n.Path}
+
+	case *ArrayPattern:
+		out = &jsonNode{Kind: "ArrayPattern", Token: encodeToken(n.Token),
+			Elements: list(len(n.Elements), func(i int) Node { return n.Elements[i] }),
+			Rest:     one(n.Rest)}
+	case *HashPattern:
+		out = &jsonNode{Kind: "HashPattern", Token: encodeToken(n.Token),
+			Keys: list(len(n.Keys), func(i int) Node { return n.Keys[i] })}
+
+	case *Variable:
+		out = &jsonNode{Kind: "Variable", Token: encodeToken(n.Token), Value: encodeValue(n.Value),
+			Resolved: n.Resolved, Depth: n.Depth, Slot: n.Slot}
+	case *IntegerLiteral:
+		out = &jsonNode{Kind: "IntegerLiteral", Token: encodeToken(n.Token), Value: encodeValue(n.Value)}
+		if n.Big != nil {
+			out.Value = encodeValue(n.Big)
+		}
+	case *FloatLiteral:
+		out = &jsonNode{Kind: "FloatLiteral", Token: encodeToken(n.Token), Value: encodeValue(n.Value)}
+	case *StringLiteral:
+		out = &jsonNode{Kind: "StringLiteral", Token: encodeToken(n.Token), Value: encodeValue(n.Value)}
+	case *Boolean:
+		out = &jsonNode{Kind: "Boolean", Token: encodeToken(n.Token), Value: encodeValue(n.Value)}
+	case *NullLiteral:
+		out = &jsonNode{Kind: "NullLiteral", Token: encodeToken(n.Token)}
+
+	default:
+		return nil, fmt.Errorf("no se puede codificar el nodo %T", node)
+	}
+	return out, err
+}
+
+// ---------------------------Decodificar--------------------------------
+
+func decodeToken(t *jsonToken) token.Token {
+	if t == nil {
+		return token.Token{}
+	}
+	return token.Token{Type: t.Type, Literal: t.Literal, Line: t.Line, Column: t.Column}
+}
+
+func decodeNode(n *jsonNode) (Node, error) {
+	if n == nil {
+		return nil, nil
+	}
+
+	var err error
+	expression := func(child *jsonNode) (e Expression) {
+		if err == nil {
+			e, err = decodeAs[Expression](n.Kind, child, "una expresion")
+		}
+		return e
+	}
+	statement := func(child *jsonNode) (s Statement) {
+		if err == nil {
+			s, err = decodeAs[Statement](n.Kind, child, "una sentencia")
+		}
+		return s
+	}
+	pattern := func(child *jsonNode) (p Pattern) {
+		if err == nil {
+			p, err = decodeAs[Pattern](n.Kind, child, "un patron")
+		}
+		return p
+	}
+	variable := func(child *jsonNode) (v *Variable) {
+		if err == nil {
+			v, err = decodeAs[*Variable](n.Kind, child, "un Variable")
+		}
+		return v
+	}
+	block := func(child *jsonNode) (b *BlockStatement) {
+		if err == nil {
+			b, err = decodeAs[*BlockStatement](n.Kind, child, "un BlockStatement")
+		}
+		return b
+	}
+	// need marca un hijo como obligatorio: si falta o es null, el nodo no
+	// se puede armar.
+	need := func(child *jsonNode, field string) *jsonNode {
+		if child == nil && err == nil {
+			err = fmt.Errorf("%s: falta %s", n.Kind, field)
+		}
+		return child
+	}
+	value := func(into interface{}) {
+		if err == nil {
+			err = json.Unmarshal(n.Value, into)
+		}
+	}
+	tok := decodeToken(n.Token)
+
+	var out Node
+	switch n.Kind {
+	case "Program":
+		program := &Program{Statements: []Statement{}}
+		for _, s := range n.Statements {
+			program.Statements = append(program.Statements, statement(need(s, "statements")))
+		}
+		out = program
+
+	case "LetStatement":
+		stmt := &LetStatement{Token: tok}
+		if n.Pattern != nil {
+			stmt.Pattern = pattern(n.Pattern)
+		} else {
+			stmt.Name = variable(need(n.Name, "name"))
+		}
+		var valueNode *jsonNode
+		if len(n.Value) > 0 {
+			value(&valueNode)
+		}
+		stmt.Value = expression(need(valueNode, "value"))
+		out = stmt
+	case "ReturnStatement":
+		out = &ReturnStatement{Token: tok, ReturnValue: expression(need(n.ReturnValue, "returnValue"))}
+	case "ExpressionStatement":
+		out = &ExpressionStatement{Token: tok, Expression: expression(need(n.Expression, "expression"))}
+	case "BlockStatement":
+		b := &BlockStatement{Token: tok, Statements: []Statement{}}
+		for _, s := range n.Statements {
+			b.Statements = append(b.Statements, statement(need(s, "statements")))
+		}
+		out = b
+
+	case "IfExpression":
+		out = &IfExpression{Token: tok, Condition: expression(need(n.Condition, "condition")),
+			Consequence: block(need(n.Consequence, "consequence")), Alternative: block(n.Alternative)}
+	case "FunctionLiteral":
+		fl := &FunctionLiteral{Token: tok, Parameters: []Pattern{}}
+		for _, p := range n.Parameters {
+			fl.Parameters = append(fl.Parameters, pattern(need(p, "parameters")))
+		}
+		fl.Body = block(need(n.Body, "body"))
+		out = fl
+	case "MacroLiteral":
+		ml := &MacroLiteral{Token: tok, Parameters: []*Variable{}}
+		for _, p := range n.Parameters {
+			ml.Parameters = append(ml.Parameters, variable(need(p, "parameters")))
+		}
+		ml.Body = block(need(n.Body, "body"))
+		out = ml
+	case "CallExpression":
+		ce := &CallExpression{Token: tok, Function: expression(need(n.Function, "function")), Arguments: []Expression{}}
+		for _, a := range n.Arguments {
+			ce.Arguments = append(ce.Arguments, expression(need(a, "arguments")))
+		}
+		out = ce
+	case "PrefixExpression":
+		out = &PrefixExpression{Token: tok, Operator: n.Operator, Right: expression(need(n.Right, "right"))}
+	case "InfixExpression":
+		out = &InfixExpression{Token: tok, Operator: n.Operator,
+			Left: expression(need(n.Left, "left")), Right: expression(need(n.Right, "right"))}
+	case "IndexExpression":
+		out = &IndexExpression{Token: tok, Left: expression(need(n.Left, "left")),
+			Index: expression(need(n.Index, "index")), Optional: n.Optional}
+	case "ArrayLiteral":
+		al := &ArrayLiteral{Token: tok, Elements: []Expression{}}
+		for _, e := range n.Elements {
+			al.Elements = append(al.Elements, expression(need(e, "elements")))
+		}
+		out = al
+	case "HashLiteral":
+		hl := &HashLiteral{Token: tok, Pairs: []HashPair{}}
+		for _, pair := range n.Pairs {
+			hl.Pairs = append(hl.Pairs, HashPair{
+				Key:   expression(need(pair.Key, "key")),
+				Value: expression(need(pair.Value, "value")),
+			})
+		}
+		out = hl
+	case "ImportExpression":
+		out = &ImportExpression{Token: tok, Path: n.Path}
+
+	case "ArrayPattern":
+		ap := &ArrayPattern{Token: tok}
+		for _, e := range n.Elements {
+			ap.Elements = append(ap.Elements, pattern(need(e, "elements")))
+		}
+		ap.Rest = variable(n.Rest)
+		out = ap
+	case "HashPattern":
+		hp := &HashPattern{Token: tok}
+		for _, k := range n.Keys {
+			hp.Keys = append(hp.Keys, variable(need(k, "keys")))
+		}
+		out = hp
+
+	case "Variable":
+		v := &Variable{Token: tok, Resolved: n.Resolved, Depth: n.Depth, Slot: n.Slot}
+		value(&v.Value)
+		out = v
+	case "IntegerLiteral":
+		il := &IntegerLiteral{Token: tok}
+		n := new(big.Int)
+		value(n)
+		if n.IsInt64() {
+			il.Value = n.Int64()
+		} else {
+			il.Big = n
+		}
+		out = il
+	case "FloatLiteral":
+		fl := &FloatLiteral{Token: tok}
+		value(&fl.Value)
+		out = fl
+	case "StringLiteral":
+		sl := &StringLiteral{Token: tok}
+		value(&sl.Value)
+		out = sl
+	case "Boolean":
+		b := &Boolean{Token: tok}
+		value(&b.Value)
+		out = b
+	case "NullLiteral":
+		out = &NullLiteral{Token: tok}
+
+	default:
+		return nil, fmt.Errorf("tipo de nodo desconocido en JSON: %q", n.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// decodeAs decodifica child, un hijo de un nodo parent, como un T. Si falta
+// devuelve el cero de T; si es de otra clase, como un LetStatement donde va
+// una expresion, es un error en lugar de un nil que falle mas adelante.
+func decodeAs[T Node](parent string, child *jsonNode, what string) (T, error) {
+	var zero T
+	if child == nil {
+		return zero, nil
+	}
+	decoded, err := decodeNode(child)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := decoded.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s: se esperaba %s, se obtuvo: %s", parent, what, child.Kind)
+	}
+	return t, nil
+}
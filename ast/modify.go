@@ -0,0 +1,115 @@
+package ast
+
+import "fmt"
+
+// ModifierFunc recibe cada nodo despues de modificar sus hijos y devuelve el
+// nodo que lo reemplaza.
+type ModifierFunc func(Node) Node
+
+// Modify recorre el arbol de abajo hacia arriba reemplazando cada nodo por
+// lo que devuelva modifier. Igual que Walk, cubre todos los tipos de nodo y
+// hace panic con uno desconocido.
+func Modify(node Node, modifier ModifierFunc) Node {
+	if isNil(node) {
+		return node
+	}
+
+	switch node := node.(type) {
+
+	case *Program:
+		for i, statement := range node.Statements {
+			node.Statements[i], _ = Modify(statement, modifier).(Statement)
+		}
+
+	// Statements
+	case *ExpressionStatement:
+		node.Expression, _ = Modify(node.Expression, modifier).(Expression)
+
+	case *BlockStatement:
+		for i := range node.Statements {
+			node.Statements[i], _ = Modify(node.Statements[i], modifier).(Statement)
+		}
+
+	case *ReturnStatement:
+		node.ReturnValue, _ = Modify(node.ReturnValue, modifier).(Expression)
+
+	case *LetStatement:
+		if node.Pattern != nil {
+			node.Pattern, _ = Modify(node.Pattern, modifier).(Pattern)
+		} else if node.Name != nil {
+			node.Name, _ = Modify(node.Name, modifier).(*Variable)
+		}
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	// Expresiones
+	case *InfixExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		node.Right, _ = Modify(node.Right, modifier).(Expression)
+
+	case *PrefixExpression:
+		node.Right, _ = Modify(node.Right, modifier).(Expression)
+
+	case *IndexExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		node.Index, _ = Modify(node.Index, modifier).(Expression)
+
+	case *IfExpression:
+		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		node.Consequence, _ = Modify(node.Consequence, modifier).(*BlockStatement)
+		if node.Alternative != nil {
+			node.Alternative, _ = Modify(node.Alternative, modifier).(*BlockStatement)
+		}
+
+	case *FunctionLiteral:
+		for i := range node.Parameters {
+			node.Parameters[i], _ = Modify(node.Parameters[i], modifier).(Pattern)
+		}
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *MacroLiteral:
+		for i := range node.Parameters {
+			node.Parameters[i], _ = Modify(node.Parameters[i], modifier).(*Variable)
+		}
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *CallExpression:
+		node.Function, _ = Modify(node.Function, modifier).(Expression)
+		for i := range node.Arguments {
+			node.Arguments[i], _ = Modify(node.Arguments[i], modifier).(Expression)
+		}
+
+	case *ArrayLiteral:
+		for i := range node.Elements {
+			node.Elements[i], _ = Modify(node.Elements[i], modifier).(Expression)
+		}
+
+	case *HashLiteral:
+		for i, pair := range node.Pairs {
+			node.Pairs[i].Key, _ = Modify(pair.Key, modifier).(Expression)
+			node.Pairs[i].Value, _ = Modify(pair.Value, modifier).(Expression)
+		}
+
+	// Patrones
+	case *ArrayPattern:
+		for i := range node.Elements {
+			node.Elements[i], _ = Modify(node.Elements[i], modifier).(Pattern)
+		}
+		if node.Rest != nil {
+			node.Rest, _ = Modify(node.Rest, modifier).(*Variable)
+		}
+
+	case *HashPattern:
+		for i := range node.Keys {
+			node.Keys[i], _ = Modify(node.Keys[i], modifier).(*Variable)
+		}
+
+	// Hojas
+	case *Variable, *IntegerLiteral, *FloatLiteral, *StringLiteral,
+		*Boolean, *NullLiteral, *ImportExpression:
+
+	default:
+		panic(fmt.Sprintf("ast.Modify: tipo de nodo inesperado %T", node))
+	}
+
+	return modifier(node)
+}
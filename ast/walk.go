@@ -0,0 +1,200 @@
+package ast
+
+import (
+	"fmt"
+	"main/token"
+	"reflect"
+)
+
+// Visitor recibe cada nodo en Walk. Si devuelve un Visitor w distinto de
+// nil, Walk visita los hijos del nodo con w y luego llama w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk recorre el arbol en profundidad empezando por node. Conoce todos los
+// tipos de nodo; un tipo nuevo que no se agregue aqui hace panic en vez de
+// saltarse en silencio.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	for _, child := range Children(node) {
+		Walk(v, child)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect recorre el arbol llamando f(node) para cada nodo; si f devuelve
+// false no se visitan los hijos de ese nodo. Al terminar los hijos se llama
+// f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Children devuelve los hijos directos de node en el orden del codigo fuente.
+// Los hijos opcionales que son nil no se incluyen.
+func Children(node Node) []Node {
+	children := []Node{}
+	add := func(n Node) {
+		if !isNil(n) {
+			children = append(children, n)
+		}
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, s := range n.Statements {
+			add(s)
+		}
+
+	// Statements
+	case *LetStatement:
+		add(n.Target())
+		add(n.Value)
+	case *ReturnStatement:
+		add(n.ReturnValue)
+	case *ExpressionStatement:
+		add(n.Expression)
+	case *BlockStatement:
+		for _, s := range n.Statements {
+			add(s)
+		}
+
+	// Expresiones
+	case *IfExpression:
+		add(n.Condition)
+		add(n.Consequence)
+		add(n.Alternative)
+	case *FunctionLiteral:
+		for _, p := range n.Parameters {
+			add(p)
+		}
+		add(n.Body)
+	case *MacroLiteral:
+		for _, p := range n.Parameters {
+			add(p)
+		}
+		add(n.Body)
+	case *CallExpression:
+		add(n.Function)
+		for _, a := range n.Arguments {
+			add(a)
+		}
+	case *PrefixExpression:
+		add(n.Right)
+	case *InfixExpression:
+		add(n.Left)
+		add(n.Right)
+	case *IndexExpression:
+		add(n.Left)
+		add(n.Index)
+	case *ArrayLiteral:
+		for _, e := range n.Elements {
+			add(e)
+		}
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			add(pair.Key)
+			add(pair.Value)
+		}
+
+	// Patrones
+	case *ArrayPattern:
+		for _, e := range n.Elements {
+			add(e)
+		}
+		add(n.Rest)
+	case *HashPattern:
+		for _, k := range n.Keys {
+			add(k)
+		}
+
+	// Hojas
+	case *Variable, *IntegerLiteral, *FloatLiteral, *StringLiteral,
+		*Boolean, *NullLiteral, *ImportExpression:
+
+	default:
+		panic(fmt.Sprintf("ast.Children: tipo de nodo inesperado %T", n))
+	}
+
+	return children
+}
+
+// TokenOf devuelve el token que guarda node, el que da su posicion en los
+// mensajes. Program no tiene uno y da el token vacio.
+func TokenOf(node Node) token.Token {
+	switch n := node.(type) {
+	// Statements
+	case *LetStatement:
+		return n.Token
+	case *ReturnStatement:
+		return n.Token
+	case *ExpressionStatement:
+		return n.Token
+	case *BlockStatement:
+		return n.Token
+
+	// Expresiones
+	case *Variable:
+		return n.Token
+	case *IntegerLiteral:
+		return n.Token
+	case *FloatLiteral:
+		return n.Token
+	case *StringLiteral:
+		return n.Token
+	case *Boolean:
+		return n.Token
+	case *NullLiteral:
+		return n.Token
+	case *PrefixExpression:
+		return n.Token
+	case *InfixExpression:
+		return n.Token
+	case *IfExpression:
+		return n.Token
+	case *FunctionLiteral:
+		return n.Token
+	case *MacroLiteral:
+		return n.Token
+	case *CallExpression:
+		return n.Token
+	case *IndexExpression:
+		return n.Token
+	case *ArrayLiteral:
+		return n.Token
+	case *HashLiteral:
+		return n.Token
+	case *ImportExpression:
+		return n.Token
+
+	// Patrones
+	case *ArrayPattern:
+		return n.Token
+	case *HashPattern:
+		return n.Token
+	}
+	return token.Token{}
+}
+
+// isNil detecta tanto un Node nil como un puntero nil guardado en un Node,
+// como un Alternative que no existe.
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,190 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// allNodes arma un programa con un nodo de cada tipo.
+func allNodes() *Program {
+	v := func(name string) *Variable { return &Variable{Value: name} }
+	block := func(exp Expression) *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: exp}}}
+	}
+	return &Program{Statements: []Statement{
+		&LetStatement{Name: v("a"), Value: &IntegerLiteral{Value: 1}},
+		&LetStatement{
+			Pattern: &ArrayPattern{
+				Elements: []Pattern{v("b"), &HashPattern{Keys: []*Variable{v("c")}}},
+				Rest:     v("d"),
+			},
+			Value: &ArrayLiteral{Elements: []Expression{&FloatLiteral{Value: 1.5}}},
+		},
+		&LetStatement{Name: v("m"), Value: &MacroLiteral{Parameters: []*Variable{v("x")}, Body: block(v("x"))}},
+		&ExpressionStatement{Expression: &IfExpression{
+			Condition:   &PrefixExpression{Operator: "!", Right: &Boolean{Value: true}},
+			Consequence: block(&NullLiteral{}),
+			Alternative: block(&InfixExpression{Left: v("a"), Operator: "+", Right: v("b")}),
+		}},
+		&ExpressionStatement{Expression: &CallExpression{
+			Function: &FunctionLiteral{Parameters: []Pattern{v("y")}, Body: &BlockStatement{
+				Statements: []Statement{&ReturnStatement{ReturnValue: v("y")}},
+			}},
+			Arguments: []Expression{&IndexExpression{
+				Left:  &HashLiteral{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: v("c")}}},
+				Index: &StringLiteral{Value: "k"},
+			}},
+		}},
+		&ExpressionStatement{Expression: &ImportExpression{Path: "lib.sp"}},
+	}}
+}
+
+func nodeName(n Node) string {
+	name := reflect.TypeOf(n).Elem().Name()
+	if v, ok := n.(*Variable); ok {
+		return name + ":" + v.Value
+	}
+	return name
+}
+
+func TestInspectVisitsEveryNodeInOrder(t *testing.T) {
+	visited := []string{}
+	Inspect(allNodes(), func(n Node) bool {
+		if n != nil {
+			visited = append(visited, nodeName(n))
+		}
+		return true
+	})
+
+	expected := []string{
+		"Program",
+		"LetStatement", "Variable:a", "IntegerLiteral",
+		"LetStatement", "ArrayPattern", "Variable:b", "HashPattern", "Variable:c", "Variable:d",
+		"ArrayLiteral", "FloatLiteral",
+		"LetStatement", "Variable:m", "MacroLiteral", "Variable:x", "BlockStatement",
+		"ExpressionStatement", "Variable:x",
+		"ExpressionStatement", "IfExpression", "PrefixExpression", "Boolean",
+		"BlockStatement", "ExpressionStatement", "NullLiteral",
+		"BlockStatement", "ExpressionStatement", "InfixExpression", "Variable:a", "Variable:b",
+		"ExpressionStatement", "CallExpression", "FunctionLiteral", "Variable:y",
+		"BlockStatement", "ReturnStatement", "Variable:y",
+		"IndexExpression", "HashLiteral", "StringLiteral", "Variable:c", "StringLiteral",
+		"ExpressionStatement", "ImportExpression",
+	}
+
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("orden erroneo.\nObtuvo:    %v\nEn vez de: %v", visited, expected)
+	}
+}
+
+func TestInspectCanSkipChildren(t *testing.T) {
+	count := 0
+	Inspect(allNodes(), func(n Node) bool {
+		if n == nil {
+			return false
+		}
+		count++
+		_, isFunction := n.(*FunctionLiteral)
+		_, isMacro := n.(*MacroLiteral)
+		return !isFunction && !isMacro
+	})
+	if count != 37 {
+		t.Errorf("numero de nodos erroneo. Obtuvo: %d, en vez de: %d", count, 37)
+	}
+}
+
+type countingVisitor struct {
+	enter, leave *int
+}
+
+func (v countingVisitor) Visit(n Node) Visitor {
+	if n == nil {
+		*v.leave++
+		return nil
+	}
+	*v.enter++
+	return v
+}
+
+func TestWalkCallsVisitNilAfterChildren(t *testing.T) {
+	enter, leave := 0, 0
+	Walk(countingVisitor{&enter, &leave}, allNodes())
+	if enter != 45 || leave != 45 {
+		t.Errorf("visitas erroneas. enter=%d leave=%d, en vez de 45", enter, leave)
+	}
+}
+
+func TestModifyRenamesEveryVariable(t *testing.T) {
+	program := allNodes()
+	Modify(program, func(n Node) Node {
+		if v, ok := n.(*Variable); ok {
+			v.Value = "_" + v.Value
+		}
+		return n
+	})
+
+	Inspect(program, func(n Node) bool {
+		if v, ok := n.(*Variable); ok && v.Value[0] != '_' {
+			t.Errorf("la variable %s no fue modificada", v.Value)
+		}
+		return true
+	})
+}
+
+type unknownNode struct{}
+
+func (u *unknownNode) TokenLiteral() string { return "" }
+func (u *unknownNode) String() string       { return "" }
+
+func TestUnknownNodeTypePanics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"Walk":   func() { Inspect(&unknownNode{}, func(Node) bool { return true }) },
+		"Modify": func() { Modify(&unknownNode{}, func(n Node) Node { return n }) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s deberia hacer panic con un nodo desconocido", name)
+				} else if msg := fmt.Sprint(r); msg == "" {
+					t.Errorf("%s hizo panic sin mensaje", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// TestTokenOfKnowsEveryNode le pone un token a cada nodo de allNodes y
+// revisa que TokenOf lo devuelva.
+func TestTokenOfKnowsEveryNode(t *testing.T) {
+	Inspect(allNodes(), func(n Node) bool {
+		if n == nil {
+			return true
+		}
+		if _, ok := n.(*Program); ok {
+			return true
+		}
+		field := reflect.ValueOf(n).Elem().FieldByName("Token")
+		field.FieldByName("Literal").SetString(nodeName(n))
+		if got := TokenOf(n).Literal; got != nodeName(n) {
+			t.Errorf("TokenOf(%s) devolvio %q", nodeName(n), got)
+		}
+		return true
+	})
+}
+
+func TestPatternVariables(t *testing.T) {
+	v := func(name string) *Variable { return &Variable{Value: name} }
+	pattern := &ArrayPattern{
+		Elements: []Pattern{v("a"), &HashPattern{Keys: []*Variable{v("b"), v("c")}}, &ArrayPattern{Elements: []Pattern{v("d")}}},
+		Rest:     v("e"),
+	}
+	names := []string{}
+	for _, variable := range PatternVariables(pattern) {
+		names = append(names, variable.Value)
+	}
+	if fmt.Sprint(names) != "[a b c d e]" {
+		t.Errorf("PatternVariables devolvio %v", names)
+	}
+}
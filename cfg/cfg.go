@@ -0,0 +1,208 @@
+package cfg
+
+import (
+	"main/ast"
+)
+
+// Block es un bloque basico: sentencias que corren una tras otra sin saltos
+// en medio. Si termina en un LoverEra, Cond es ese LoverEra y Succs tiene la
+// rama verdadera y despues la falsa.
+type Block struct {
+	ID    int
+	Stmts []ast.Statement
+	Cond  *ast.IfExpression
+	Succs []*Block
+	Preds []*Block
+}
+
+// Graph es el grafo de flujo de control del codigo de afuera de las
+// funciones o del cuerpo de una funcion. Entry es el primer bloque y Exit un
+// bloque vacio al que llegan los hi y el final del codigo.
+//
+// Solo los LoverEra que son una sentencia por si mismos abren ramas, porque
+// solo ahi un hi de sus ramas sale de la funcion; los que estan dentro de
+// otra expresion quedan dentro de su sentencia.
+type Graph struct {
+	Name   string
+	Node   ast.Node // *ast.Program o *ast.FunctionLiteral
+	Entry  *Block
+	Exit   *Block
+	Blocks []*Block // En orden de ID: Entry primero y Exit al final
+}
+
+// Build arma el grafo del codigo de afuera de las funciones y uno por cada
+// isme del programa, incluidas las anidadas, en el orden en que aparecen.
+// Las funciones que se asignan con enchanted llevan ese nombre.
+func Build(program *ast.Program) []*Graph {
+	graphs := []*Graph{New("main", program, program.Statements)}
+
+	names := map[*ast.FunctionLiteral]string{}
+	ast.Inspect(program, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.MacroLiteral:
+			return false
+		case *ast.LetStatement:
+			if fn, ok := n.Value.(*ast.FunctionLiteral); ok && n.Name != nil {
+				names[fn] = n.Name.Value
+			}
+		case *ast.FunctionLiteral:
+			name, ok := names[n]
+			if !ok {
+				name = "isme " + n.Token.Position()
+			}
+			var body []ast.Statement
+			if n.Body != nil {
+				body = n.Body.Statements
+			}
+			graphs = append(graphs, New(name, n, body))
+		}
+		return true
+	})
+	return graphs
+}
+
+// New arma el grafo de una lista de sentencias.
+func New(name string, node ast.Node, stmts []ast.Statement) *Graph {
+	g := &Graph{Name: name, Node: node, Exit: &Block{}}
+	b := &builder{g: g}
+	g.Entry = b.newBlock()
+	connect(b.statements(stmts, g.Entry), g.Exit)
+
+	g.Blocks = append(g.Blocks, g.Exit)
+	for i, block := range g.Blocks {
+		block.ID = i
+	}
+	return g
+}
+
+type builder struct {
+	g *Graph
+}
+
+func (b *builder) newBlock() *Block {
+	block := &Block{}
+	b.g.Blocks = append(b.g.Blocks, block)
+	return block
+}
+
+// statements agrega stmts empezando en current y devuelve el bloque donde
+// sigue el flujo. current nil quiere decir que no se llega a este punto: la
+// siguiente sentencia abre un bloque sin predecesores.
+func (b *builder) statements(stmts []ast.Statement, current *Block) *Block {
+	for _, s := range stmts {
+		if current == nil {
+			current = b.newBlock()
+		}
+		switch s := s.(type) {
+		case *ast.ReturnStatement:
+			current.Stmts = append(current.Stmts, s)
+			connect(current, b.g.Exit)
+			current = nil
+		case *ast.ExpressionStatement:
+			if ifExp, ok := s.Expression.(*ast.IfExpression); ok {
+				current = b.ifExpression(ifExp, current)
+				continue
+			}
+			current.Stmts = append(current.Stmts, s)
+		default:
+			current.Stmts = append(current.Stmts, s)
+		}
+	}
+	return current
+}
+
+func (b *builder) ifExpression(ifExp *ast.IfExpression, current *Block) *Block {
+	current.Cond = ifExp
+
+	consequence := b.newBlock()
+	connect(current, consequence)
+	ends := []*Block{b.statements(blockStatements(ifExp.Consequence), consequence)}
+	if ifExp.Alternative != nil {
+		alternative := b.newBlock()
+		connect(current, alternative)
+		ends = append(ends, b.statements(blockStatements(ifExp.Alternative), alternative))
+	} else {
+		ends = append(ends, current)
+	}
+
+	if ends[0] == nil && ends[1] == nil {
+		// Las dos ramas terminan en hi.
+		return nil
+	}
+	join := b.newBlock()
+	for _, end := range ends {
+		connect(end, join)
+	}
+	return join
+}
+
+func blockStatements(block *ast.BlockStatement) []ast.Statement {
+	if block == nil {
+		return nil
+	}
+	return block.Statements
+}
+
+func connect(from, to *Block) {
+	if from == nil {
+		return
+	}
+	from.Succs = append(from.Succs, to)
+	to.Preds = append(to.Preds, from)
+}
+
+// ---------------------------Analisis--------------------------------
+
+// Reachable devuelve los bloques a los que se llega desde Entry.
+func (g *Graph) Reachable() map[*Block]bool {
+	seen := map[*Block]bool{}
+	var visit func(b *Block)
+	visit = func(b *Block) {
+		if seen[b] {
+			return
+		}
+		seen[b] = true
+		for _, s := range b.Succs {
+			visit(s)
+		}
+	}
+	visit(g.Entry)
+	return seen
+}
+
+// Unreachable devuelve, en orden, los bloques con codigo a los que no se
+// llega desde Entry.
+func (g *Graph) Unreachable() []*Block {
+	reachable := g.Reachable()
+	blocks := []*Block{}
+	for _, b := range g.Blocks {
+		if !reachable[b] && (len(b.Stmts) > 0 || b.Cond != nil) {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// ReversePostorder devuelve los bloques alcanzables en un orden donde cada
+// bloque va antes que sus sucesores, el que usan los analisis de flujo de
+// datos hacia adelante.
+func (g *Graph) ReversePostorder() []*Block {
+	seen := map[*Block]bool{}
+	order := []*Block{}
+	var visit func(b *Block)
+	visit = func(b *Block) {
+		seen[b] = true
+		for _, s := range b.Succs {
+			if !seen[s] {
+				visit(s)
+			}
+		}
+		order = append(order, b)
+	}
+	visit(g.Entry)
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
@@ -0,0 +1,199 @@
+package cfg
+
+import (
+	"bytes"
+	"fmt"
+	"main/ast"
+	"main/lexer"
+	"main/parser"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores al parsear %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+// describe pone un bloque por linea: sus sentencias, la condicion si tiene y
+// sus sucesores.
+func describe(g *Graph) string {
+	lines := []string{}
+	for _, b := range g.Blocks {
+		parts := []string{}
+		for _, s := range b.Stmts {
+			parts = append(parts, s.String())
+		}
+		if b.Cond != nil {
+			parts = append(parts, "LoverEra "+b.Cond.Condition.String())
+		}
+		succs := []string{}
+		for _, s := range b.Succs {
+			succs = append(succs, fmt.Sprintf("B%d", s.ID))
+		}
+		lines = append(lines, fmt.Sprintf("B%d [%s] -> %s", b.ID,
+			strings.Join(parts, " | "), strings.Join(succs, " ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"enchanted x = 1; x",
+			"B0 [enchanted x = 1; | x] -> B1\n" +
+				"B1 [] -> ",
+		},
+		{
+			"enchanted x = 1; LoverEra (x < 2) { 3 } RepEra { 4 }; x",
+			"B0 [enchanted x = 1; | LoverEra (x < 2)] -> B1 B2\n" +
+				"B1 [3] -> B3\n" +
+				"B2 [4] -> B3\n" +
+				"B3 [x] -> B4\n" +
+				"B4 [] -> ",
+		},
+		{
+			"LoverEra (x) { 3 }; 5",
+			"B0 [LoverEra x] -> B1 B2\n" +
+				"B1 [3] -> B2\n" +
+				"B2 [5] -> B3\n" +
+				"B3 [] -> ",
+		},
+		{
+			"hi 1; 2; 3",
+			"B0 [hi 1;] -> B2\n" +
+				"B1 [2 | 3] -> B2\n" +
+				"B2 [] -> ",
+		},
+		{
+			"LoverEra (x) { hi 1; } RepEra { hi 2; }; 3",
+			"B0 [LoverEra x] -> B1 B2\n" +
+				"B1 [hi 1;] -> B4\n" +
+				"B2 [hi 2;] -> B4\n" +
+				"B3 [3] -> B4\n" +
+				"B4 [] -> ",
+		},
+		{
+			"LoverEra (x) { LoverEra (y) { hi 1; }; 2 } RepEra { 3 }",
+			"B0 [LoverEra x] -> B1 B4\n" +
+				"B1 [LoverEra y] -> B2 B3\n" +
+				"B2 [hi 1;] -> B6\n" +
+				"B3 [2] -> B5\n" +
+				"B4 [3] -> B5\n" +
+				"B5 [] -> B6\n" +
+				"B6 [] -> ",
+		},
+		{
+			// Un LoverEra dentro de otra expresion no abre ramas.
+			"enchanted y = LoverEra (x) { 1 } RepEra { 2 };",
+			"B0 [enchanted y = LoverErax 1RepEra 2;] -> B1\n" +
+				"B1 [] -> ",
+		},
+		{
+			"",
+			"B0 [] -> B1\n" +
+				"B1 [] -> ",
+		},
+	}
+
+	for _, tt := range tests {
+		got := describe(New("main", nil, parse(t, tt.input).Statements))
+		if got != tt.expected {
+			t.Errorf("%q: grafo erroneo.\nesperado:\n%s\nobtenido:\n%s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestBuildFunctions(t *testing.T) {
+	input := "enchanted f = isme(a) { enchanted g = isme() { hi a; }; g() };\nSpeakNow(isme(x) { x });"
+	graphs := Build(parse(t, input))
+
+	names := []string{}
+	for _, g := range graphs {
+		names = append(names, g.Name)
+	}
+	expected := []string{"main", "f", "g", "isme 2:10"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("grafos erroneos. esperado=%v, obtenido=%v", expected, names)
+	}
+	if _, ok := graphs[1].Node.(*ast.FunctionLiteral); !ok {
+		t.Errorf("el grafo de f deberia apuntar a su isme. obtenido=%T", graphs[1].Node)
+	}
+	if got := describe(graphs[2]); got != "B0 [hi a;] -> B1\nB1 [] -> " {
+		t.Errorf("grafo de g erroneo:\n%s", got)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{"1; 2", []int{}},
+		{"hi 1; 2", []int{1}},
+		{"LoverEra (x) { hi 1; } RepEra { hi 2; }; 3", []int{3}},
+		{"LoverEra (x) { hi 1; }; 3", []int{}},
+		{"hi 1; LoverEra (x) { 2 } RepEra { 3 }; 4", []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		g := New("main", nil, parse(t, tt.input).Statements)
+		ids := []int{}
+		for _, b := range g.Unreachable() {
+			ids = append(ids, b.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+			t.Errorf("%q: bloques inalcanzables erroneos. esperado=%v, obtenido=%v",
+				tt.input, tt.expected, ids)
+		}
+	}
+}
+
+func TestReversePostorder(t *testing.T) {
+	g := New("main", nil, parse(t, "LoverEra (x) { 1 } RepEra { 2 }; 3").Statements)
+	position := map[*Block]int{}
+	for i, b := range g.ReversePostorder() {
+		position[b] = i
+	}
+	if len(position) != len(g.Blocks) {
+		t.Fatalf("se esperaban %d bloques, se obtuvieron %d", len(g.Blocks), len(position))
+	}
+	for _, b := range g.Blocks {
+		for _, s := range b.Succs {
+			if position[b] >= position[s] {
+				t.Errorf("B%d deberia ir antes que su sucesor B%d", b.ID, s.ID)
+			}
+		}
+	}
+}
+
+func TestWriteDot(t *testing.T) {
+	program := parse(t, `enchanted f = isme() { hi "a"; 1 }; LoverEra (f()) { 2 }`)
+	var out bytes.Buffer
+	if err := WriteDot(&out, Build(program)); err != nil {
+		t.Fatalf("WriteDot fallo: %v", err)
+	}
+	dot := out.String()
+
+	for _, expected := range []string{
+		"digraph CFG {",
+		"subgraph cluster_0 {\n    label=\"main\";",
+		"subgraph cluster_1 {\n    label=\"f\";",
+		`g0_0 -> g0_1 [label="SparksFly"];`,
+		`g0_0 -> g0_2 [label="BadBlood"];`,
+		`g1_0 [label="B0 (entry)\lhi a;\l"];`,
+		`g1_1 [label="B1\l1\l", style=dashed];`,
+		`g1_2 [label="B2 (exit)\l"];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("se esperaba %q en:\n%s", expected, dot)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cfg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLabel es el largo maximo de una sentencia en el diagrama.
+const maxLabel = 40
+
+// WriteDot escribe los grafos en formato Graphviz, cada uno en su propio
+// cluster. Los bloques inalcanzables van punteados.
+func WriteDot(w io.Writer, graphs []*Graph) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "digraph CFG {")
+	fmt.Fprintln(out, "  node [shape=box];")
+	for i, g := range graphs {
+		writeGraph(out, g, fmt.Sprintf("g%d_", i), i)
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+func writeGraph(out io.Writer, g *Graph, prefix string, cluster int) {
+	fmt.Fprintf(out, "  subgraph cluster_%d {\n", cluster)
+	fmt.Fprintf(out, "    label=\"%s\";\n", escape(g.Name))
+
+	reachable := g.Reachable()
+	for _, b := range g.Blocks {
+		style := ""
+		if !reachable[b] {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(out, "    %s%d [label=\"%s\"%s];\n", prefix, b.ID, blockLabel(g, b), style)
+	}
+	for _, b := range g.Blocks {
+		for i, s := range b.Succs {
+			label := ""
+			if b.Cond != nil {
+				label = " [label=\"SparksFly\"]"
+				if i == 1 {
+					label = " [label=\"BadBlood\"]"
+				}
+			}
+			fmt.Fprintf(out, "    %s%d -> %s%d%s;\n", prefix, b.ID, prefix, s.ID, label)
+		}
+	}
+	fmt.Fprintln(out, "  }")
+}
+
+// blockLabel pone una sentencia por linea, alineadas a la izquierda.
+func blockLabel(g *Graph, b *Block) string {
+	header := fmt.Sprintf("B%d", b.ID)
+	switch b {
+	case g.Entry:
+		header += " (entry)"
+	case g.Exit:
+		header += " (exit)"
+	}
+
+	lines := []string{header}
+	for _, s := range b.Stmts {
+		lines = append(lines, s.String())
+	}
+	if b.Cond != nil {
+		lines = append(lines, "LoverEra "+b.Cond.Condition.String())
+	}
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > maxLabel {
+			line = string(runes[:maxLabel-3]) + "..."
+		}
+		lines[i] = escape(line)
+	}
+	return strings.Join(lines, "\\l") + "\\l"
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
@@ -3,6 +3,9 @@ package compiler
 import (
 	"fmt"
 	"main/ast"
+	"main/evaluator"
+	"main/optimizer"
+	"main/typechecker"
 	"strings"
 )
 
@@ -22,6 +25,9 @@ type SymbolTable struct {
 
 var symbolTable SymbolTable
 
+// checker guarda los tipos inferidos del programa que se esta compilando.
+var checker *typechecker.Checker
+
 func initSymbolTable() {
 	symbolTable = SymbolTable{
 		symbols: make(map[string]int),
@@ -45,6 +51,20 @@ func GenerateMIPS(node ast.Node) string {
 	stringLiterals = make(map[string]string)
 	stringCount = 0
 	initSymbolTable()
+	checker = typechecker.New()
+	checker.Predeclare(evaluator.Predeclared())
+	if program, ok := node.(*ast.Program); ok {
+		// Se optimiza una copia: el llamador sigue usando el programa tal
+		// como lo escribio el usuario. Si no se puede copiar, se compila sin
+		// optimizar.
+		if copied, err := ast.Copy(program); err == nil {
+			program = copied.(*ast.Program)
+			checker.Check(program)
+			node = optimizer.Optimize(program)
+		} else {
+			checker.Check(program)
+		}
+	}
 
 	writeLines(&output, []string{
 		".data",
@@ -274,25 +294,18 @@ func generateStringLiteral(output *strings.Builder, value string) (int, string)
 }
 
 func collectStringLiterals(node ast.Node) {
-	switch n := node.(type) {
-	case *ast.Program:
-		for _, stmt := range n.Statements {
-			collectStringLiterals(stmt)
-		}
-	case *ast.ExpressionStatement:
-		collectStringLiterals(n.Expression)
-	case *ast.CallExpression:
-		for _, arg := range n.Arguments {
-			collectStringLiterals(arg)
+	ast.Inspect(node, func(n ast.Node) bool {
+		str, ok := n.(*ast.StringLiteral)
+		if !ok {
+			return true
 		}
-	case *ast.StringLiteral:
-		if _, exists := stringLiterals[n.Value]; !exists {
+		if _, exists := stringLiterals[str.Value]; !exists {
 			label := fmt.Sprintf("str_%d", stringCount)
-			stringLiterals[n.Value] = label
+			stringLiterals[str.Value] = label
 			stringCount++
 		}
-		// ... handle other node types as needed ...
-	}
+		return true
+	})
 }
 
 // ----------------------------------- Infix Expressions --------------------------------------------------------------
@@ -427,6 +440,10 @@ func generateStringInfixExpression(output *strings.Builder, operator string, lef
 
 // ------------------------------------Variables-------------------------------------
 func generateVariableDeclaration(output *strings.Builder, node *ast.LetStatement) {
+	if node.Name == nil {
+		fmt.Printf("Unsupported destructuring declaration: %s\n", node.String())
+		return
+	}
 	varName := node.Name.Value
 	valueReg, valueType := generateNode(output, node.Value)
 
@@ -450,10 +467,31 @@ func generateVariableDeclaration(output *strings.Builder, node *ast.LetStatement
 func generateVariableAccess(output *strings.Builder, node *ast.Variable) (int, string) {
 	varName := node.Value
 	if offset, ok := symbolTable.symbols[varName]; ok {
+		valType := inferredType(node)
+		if valType == "float" {
+			reg := getNextFloatRegister()
+			writeLine(output, fmt.Sprintf("l.s $f%d, %d($sp)", reg, offset))
+			return reg, valType
+		}
 		reg := getNextRegister()
 		writeLine(output, fmt.Sprintf("lw $t%d, %d($sp)", reg, offset))
-		return reg, "int" // Assume int for simplicity, you may need to store type information
+		return reg, valType
 	}
 	fmt.Printf("Undefined variable: %s\n", varName)
 	return 0, ""
 }
+
+// inferredType traduce el tipo del checker al tipo de registro que usa el
+// generador; si no se pudo inferir se asume int.
+func inferredType(node ast.Expression) string {
+	switch checker.TypeOf(node) {
+	case typechecker.Float:
+		return "float"
+	case typechecker.Bool:
+		return "bool"
+	case typechecker.String:
+		return "string"
+	default:
+		return "int"
+	}
+}
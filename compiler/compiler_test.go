@@ -0,0 +1,23 @@
+package compiler
+
+import (
+	"main/lexer"
+	"main/parser"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMIPSFoldsConstants(t *testing.T) {
+	program := parser.New(lexer.New("enchanted segundos = 2 * 60 * 60;")).ParseProgram()
+	mips := GenerateMIPS(program)
+
+	if !strings.Contains(mips, "li $t0, 7200") {
+		t.Errorf("se esperaba la constante calculada. obtuvo:\n%s", mips)
+	}
+	if strings.Contains(mips, "mul") {
+		t.Errorf("no deberia quedar ninguna multiplicacion. obtuvo:\n%s", mips)
+	}
+	if program.String() != "enchanted segundos = ((2 * 60) * 60);" {
+		t.Errorf("GenerateMIPS no deberia cambiar el programa. obtuvo=%q", program.String())
+	}
+}
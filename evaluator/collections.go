@@ -0,0 +1,196 @@
+package evaluator
+
+import (
+	"main/object"
+	"sort"
+)
+
+// Builtins de orden superior. Llaman a su funcion con callFunction y el
+// Runtime de la llamada, asi que el callback cuenta para los presupuestos y
+// aparece en la pila; el primer error que devuelve corta el recorrido y es
+// el resultado del builtin.
+
+var functionParam = object.Param{Name: "fn", Types: []object.ObjectType{object.FUNCTION_OBJ, object.BUILTIN_OBJ}}
+
+// eachElement llama a fn con cada elemento de arr, hasta que visit devuelve
+// false o fn un error.
+func eachElement(rt *object.Runtime, arr *object.Array, fn object.Object,
+	visit func(element, result object.Object) bool) *object.Error {
+	for _, element := range arr.Elements {
+		result := callFunction(fn, []object.Object{element}, rt, "")
+		if errObj, ok := result.(*object.Error); ok {
+			return errObj
+		}
+		if !visit(element, result) {
+			break
+		}
+	}
+	return nil
+}
+
+func callbackBuiltin(name, typ string, fn func(rt *object.Runtime, arr *object.Array, callback object.Object) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Name:      name,
+		Signature: object.Signature{Params: []object.Param{arrayParam, functionParam}, Type: typ},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return fn(rt, args[0].(*object.Array), args[1])
+		},
+	}
+}
+
+var collectionBuiltins = []*object.Builtin{
+	callbackBuiltin("map", "isme([a], isme(a) b) [b]", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		elements := make([]object.Object, 0, len(arr.Elements))
+		if err := eachElement(rt, arr, fn, func(_, result object.Object) bool {
+			elements = append(elements, result)
+			return true
+		}); err != nil {
+			return err
+		}
+		return &object.Array{Elements: elements}
+	}),
+	callbackBuiltin("filter", "isme([a], isme(a) any) [a]", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		elements := []object.Object{}
+		if err := eachElement(rt, arr, fn, func(element, result object.Object) bool {
+			if isTruthy(result) {
+				elements = append(elements, element)
+			}
+			return true
+		}); err != nil {
+			return err
+		}
+		return &object.Array{Elements: elements}
+	}),
+	callbackBuiltin("find", "isme([a], isme(a) any) a", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		var found object.Object = NULL
+		if err := eachElement(rt, arr, fn, func(element, result object.Object) bool {
+			if isTruthy(result) {
+				found = element
+				return false
+			}
+			return true
+		}); err != nil {
+			return err
+		}
+		return found
+	}),
+	callbackBuiltin("any", "isme([a], isme(a) any) bool", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		found := false
+		if err := eachElement(rt, arr, fn, func(_, result object.Object) bool {
+			found = isTruthy(result)
+			return !found
+		}); err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(found)
+	}),
+	callbackBuiltin("all", "isme([a], isme(a) any) bool", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		all := true
+		if err := eachElement(rt, arr, fn, func(_, result object.Object) bool {
+			all = isTruthy(result)
+			return all
+		}); err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(all)
+	}),
+	{
+		Name: "reduce",
+		Signature: object.Signature{
+			Params: []object.Param{arrayParam, functionParam, {Name: "inicial"}},
+			Type:   "isme([a], isme(b, a) b, b) b",
+		},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			acc := args[2]
+			for _, element := range args[0].(*object.Array).Elements {
+				acc = callFunction(args[1], []object.Object{acc, element}, rt, "")
+				if isError(acc) {
+					return acc
+				}
+			}
+			return acc
+		},
+	},
+	{
+		Name:      "sort",
+		Signature: object.Signature{Params: []object.Param{arrayParam}, Type: "isme([a]) [a]"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return sortElements(args[0].(*object.Array), naturalLess)
+		},
+	},
+	callbackBuiltin("sortBy", "isme([a], isme(a, a) any) [a]", func(rt *object.Runtime, arr *object.Array, fn object.Object) object.Object {
+		return sortElements(arr, func(a, b object.Object) (bool, object.Object) {
+			result := callFunction(fn, []object.Object{a, b}, rt, "")
+			if isError(result) {
+				return false, result
+			}
+			return isTruthy(result), nil
+		})
+	}),
+	{
+		Name: "zip",
+		Signature: object.Signature{
+			Params: []object.Param{
+				{Name: "a", Types: arrayParam.Types},
+				{Name: "b", Types: arrayParam.Types},
+			},
+			Type: "isme([a], [b]) [[any]]",
+		},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			a, b := args[0].(*object.Array).Elements, args[1].(*object.Array).Elements
+			pairs := make([]object.Object, min(len(a), len(b)))
+			for i := range pairs {
+				pairs[i] = &object.Array{Elements: []object.Object{a[i], b[i]}}
+			}
+			return &object.Array{Elements: pairs}
+		},
+	},
+	{
+		Name:      "enumerate",
+		Signature: object.Signature{Params: []object.Param{arrayParam}, Type: "isme([a]) [[any]]"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			pairs := make([]object.Object, len(elements))
+			for i, element := range elements {
+				pairs[i] = &object.Array{Elements: []object.Object{&object.Integer{Value: int64(i)}, element}}
+			}
+			return &object.Array{Elements: pairs}
+		},
+	},
+}
+
+// sortElements devuelve una copia ordenada de arr; el orden es estable. Si
+// less devuelve un error, el orden se abandona y el error es el resultado.
+func sortElements(arr *object.Array, less func(a, b object.Object) (bool, object.Object)) object.Object {
+	elements := make([]object.Object, len(arr.Elements))
+	copy(elements, arr.Elements)
+	var err object.Object
+	sort.SliceStable(elements, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var isLess bool
+		isLess, err = less(elements[i], elements[j])
+		return isLess
+	})
+	if err != nil {
+		return err
+	}
+	return &object.Array{Elements: elements}
+}
+
+// naturalLess es el orden de sort: los numeros por valor y los strings
+// alfabeticamente. Otros tipos, o un numero con un string, no se comparan.
+func naturalLess(a, b object.Object) (bool, object.Object) {
+	if a.Type() == object.STRING_OBJ && b.Type() == object.STRING_OBJ {
+		return a.(*object.String).Value < b.(*object.String).Value, nil
+	}
+	if isNumber(a) && isNumber(b) {
+		return evalInfixExpression("<", a, b) == TRUE, nil
+	}
+	return false, createError("Tipo sin soporte para `sort`: no se puede comparar %s con %s", a.Type(), b.Type())
+}
+
+func isNumber(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.FLOAT_OBJ
+}
@@ -0,0 +1,78 @@
+package evaluator
+
+import (
+	"main/ast"
+	"main/object"
+)
+
+// bindPattern liga en env los nombres del patron con las partes de val.
+// Devuelve un error si val no tiene la forma que pide el patron.
+func bindPattern(pattern ast.Pattern, val object.Object, env *object.Environment) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.Variable:
+		define(env, pattern, val)
+		return nil
+	case *ast.ArrayPattern:
+		return bindArrayPattern(pattern, val, env)
+	case *ast.HashPattern:
+		return bindHashPattern(pattern, val, env)
+	default:
+		return createError("Patron desconocido: %T", pattern)
+	}
+}
+
+func bindArrayPattern(pattern *ast.ArrayPattern, val object.Object, env *object.Environment) *object.Error {
+	array, ok := val.(*object.Array)
+	if !ok {
+		return createError("No se puede desestructurar %s como array: %s",
+			val.Type(), pattern.String())
+	}
+	elements := array.Elements
+	if pattern.Rest == nil && len(elements) != len(pattern.Elements) {
+		return createError("El array tiene %d elementos, el patron %s necesita %d",
+			len(elements), pattern.String(), len(pattern.Elements))
+	}
+	if len(elements) < len(pattern.Elements) {
+		return createError("El array tiene %d elementos, el patron %s necesita al menos %d",
+			len(elements), pattern.String(), len(pattern.Elements))
+	}
+	for i, element := range pattern.Elements {
+		if err := bindPattern(element, elements[i], env); err != nil {
+			return err
+		}
+	}
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(elements)-len(pattern.Elements))
+		copy(rest, elements[len(pattern.Elements):])
+		define(env, pattern.Rest, &object.Array{Elements: rest})
+	}
+	return nil
+}
+
+func bindHashPattern(pattern *ast.HashPattern, val object.Object, env *object.Environment) *object.Error {
+	switch val := val.(type) {
+	case *object.Hash:
+		for _, key := range pattern.Keys {
+			hashKey := (&object.String{Value: key.Value}).HashKey()
+			pair, ok := val.Get(hashKey)
+			if !ok {
+				return createError("El hashMap no tiene la llave %q que pide el patron %s",
+					key.Value, pattern.String())
+			}
+			define(env, key, pair.Value)
+		}
+		return nil
+	case *object.Module:
+		for _, key := range pattern.Keys {
+			member, ok := val.Env.Get(key.Value)
+			if !ok {
+				return createError("El modulo %s no define: %s", val.Path, key.Value)
+			}
+			define(env, key, member)
+		}
+		return nil
+	default:
+		return createError("No se puede desestructurar %s como hashMap: %s",
+			val.Type(), pattern.String())
+	}
+}
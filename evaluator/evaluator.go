@@ -1,9 +1,14 @@
 package evaluator
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"main/ast"
 	"main/object"
+	"math"
+	"math/big"
+	"sort"
 )
 
 var (
@@ -12,7 +17,37 @@ var (
 	FALSE = &object.Bool{Value: false}
 )
 
+// EvalContext evalua node como Eval, pero corta con un error si ctx se
+// cancela o si se pasa de los limites de env.Runtime().Limits. Los
+// presupuestos se cuentan desde cero en cada llamada.
+func EvalContext(ctx context.Context, node ast.Node, env *object.Environment) object.Object {
+	env.Runtime().Begin(ctx)
+	return Eval(node, env)
+}
+
+// Eval evalua node. Un error del programa guarda la posicion del nodo mas
+// interno que lo devolvio, asi uno fuera de toda funcion tambien dice donde
+// ocurrio. Los de limites y cancelacion no: cortan donde les toca.
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	return locate(node, evalNode(node, env))
+}
+
+// locate le pone a result, si es un error del programa sin posicion, la
+// posicion de node.
+func locate(node ast.Node, result object.Object) object.Object {
+	if errObj, ok := result.(*object.Error); ok && errObj.Kind == object.ProgramError && errObj.Position == "" {
+		if tok := ast.TokenOf(node); tok.Line > 0 {
+			errObj.Position = tok.Position()
+		}
+	}
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
+	if err := env.Runtime().Step(); err != nil {
+		return err
+	}
+
 	switch node := node.(type) {
 
 	case *ast.Program:
@@ -26,20 +61,33 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
+		if node.Pattern != nil {
+			if err := bindPattern(node.Pattern, val, env); err != nil {
+				return err
+			}
+		} else {
+			define(env, node.Name, val)
+		}
 
 	case *ast.Variable:
 		return evalVariable(node, env)
 
 	case *ast.StringLiteral:
-		return &object.String{Value: node.Value}
+		return allocate(env, &object.String{Value: node.Value})
 
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return allocate(env, &object.Function{Parameters: params, Env: env, Body: body})
 
 	case *ast.CallExpression:
+		if node.Function.TokenLiteral() == "quote" {
+			if len(node.Arguments) != 1 {
+				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
+					len(node.Arguments))
+			}
+			return quote(node.Arguments[0], env)
+		}
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
@@ -48,31 +96,39 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		return callFunction(function, args, env.Runtime(), node.Token.Position())
 
 	// Enteros Literales
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		if node.Big != nil {
+			return allocate(env, &object.BigInt{Value: new(big.Int).Set(node.Big)})
+		}
+		return allocate(env, &object.Integer{Value: node.Value})
 	case *ast.FloatLiteral:
-		return &object.Float{Value: node.Value}
+		return allocate(env, &object.Float{Value: node.Value})
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return NULL
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return allocate(env, evalPrefixExpression(node.Operator, right))
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
+		if node.Operator == "??" {
+			return evalNullishExpression(node, left, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return allocate(env, evalInfixExpression(node.Operator, left, right))
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
@@ -88,25 +144,31 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(elements) == 1 && isError(elements[0]) {
 			return elements[0]
 		}
-		return &object.Array{Elements: elements}
+		return allocate(env, &object.Array{Elements: elements})
 	case *ast.IndexExpression:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
-		}
-		return evalIndexExpression(left, index)
+		result, _ := evalIndexChain(node, env)
+		return result
 	case *ast.HashLiteral:
-		return evalHashLiteral(node, env)
+		return allocate(env, evalHashLiteral(node, env))
+	case *ast.ImportExpression:
+		return evalImportExpression(node, env)
+	case *ast.MacroLiteral:
+		return createError("folklore solo puede definirse con enchanted en el primer nivel")
 
 	}
 
 	return nil
 }
 
+// allocate cuenta obj en el presupuesto de objetos y lo devuelve, o el error
+// si se paso del limite.
+func allocate(env *object.Environment, obj object.Object) object.Object {
+	if err := env.Runtime().Allocate(obj); err != nil {
+		return err
+	}
+	return obj
+}
+
 func evalIfExpression(
 	ie *ast.IfExpression,
 	env *object.Environment,
@@ -125,19 +187,31 @@ func evalIfExpression(
 	}
 }
 
+// isTruthy define que valores cuentan como verdaderos en LoverEra y `!`:
+// todo excepto BadBlood y BlankSpace.
 func isTruthy(obj object.Object) bool {
-	switch obj {
-	case NULL:
-		return false
-	case TRUE:
-		return true
-	case FALSE:
+	switch obj := obj.(type) {
+	case *object.Null:
 		return false
+	case *object.Bool:
+		return obj.Value
 	default:
 		return true
 	}
 }
 
+// evalNullishExpression evalua `a ?? b`: b solo se evalua si a es null.
+func evalNullishExpression(
+	node *ast.InfixExpression,
+	left object.Object,
+	env *object.Environment,
+) object.Object {
+	if left.Type() != object.NULL_OBJ {
+		return left
+	}
+	return Eval(node.Right, env)
+}
+
 func evalInfixExpression(
 	operator string,
 	left, right object.Object,
@@ -147,6 +221,12 @@ func evalInfixExpression(
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isInteger(left) && isInteger(right):
+		return evalBigIntInfixExpression(operator, toBigInt(left), toBigInt(right))
+	case (left.Type() == object.NULL_OBJ || right.Type() == object.NULL_OBJ) && operator == "==":
+		return nativeBoolToBooleanObject(left.Type() == right.Type())
+	case (left.Type() == object.NULL_OBJ || right.Type() == object.NULL_OBJ) && operator == "!=":
+		return nativeBoolToBooleanObject(left.Type() != right.Type())
 	case left.Type() == object.FLOAT_OBJ || right.Type() == object.FLOAT_OBJ:
 		return evalFloatInfixExpression(operator, left, right)
 	case operator == "==":
@@ -194,6 +274,8 @@ func evalFloatInfixExpression(
 		leftVal = left.(*object.Float).Value
 	case object.INTEGER_OBJ:
 		leftVal = float64(left.(*object.Integer).Value)
+	case object.BIGINT_OBJ:
+		leftVal, _ = new(big.Float).SetInt(left.(*object.BigInt).Value).Float64()
 	default:
 		return createError("Error de tipos: %s %s %s", left.Type(), operator, right.Type())
 	}
@@ -203,6 +285,8 @@ func evalFloatInfixExpression(
 		rightVal = right.(*object.Float).Value
 	case object.INTEGER_OBJ:
 		rightVal = float64(right.(*object.Integer).Value)
+	case object.BIGINT_OBJ:
+		rightVal, _ = new(big.Float).SetInt(right.(*object.BigInt).Value).Float64()
 	default:
 		return createError("Error de tipos: %s %s %s", left.Type(), operator, right.Type())
 	}
@@ -215,8 +299,10 @@ func evalFloatInfixExpression(
 	case "*":
 		return &object.Float{Value: leftVal * rightVal}
 	case "/":
+		// Dividir por cero es un error tambien con flotantes, igual que con
+		// enteros: el lenguaje no tiene Inf ni NaN.
 		if rightVal == 0 {
-			return createError("Error: División por cero")
+			return createError(divisionByZero)
 		}
 		return &object.Float{Value: leftVal / rightVal}
 	case "<":
@@ -236,7 +322,12 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	switch right.Type() {
 	case object.INTEGER_OBJ:
 		value := right.(*object.Integer).Value
+		if value == math.MinInt64 {
+			return normalizeBigInt(new(big.Int).Neg(toBigInt(right)))
+		}
 		return &object.Integer{Value: -value}
+	case object.BIGINT_OBJ:
+		return normalizeBigInt(new(big.Int).Neg(toBigInt(right)))
 	case object.FLOAT_OBJ:
 		value := right.(*object.Float).Value
 		return &object.Float{Value: -value}
@@ -245,35 +336,6 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	}
 }
 
-func evalIntegerInfixExpression(
-	operator string,
-	left, right object.Object,
-) object.Object {
-	leftVal := left.(*object.Integer).Value
-	rightVal := right.(*object.Integer).Value
-	switch operator {
-	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
-	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
-	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
-	case "/":
-		return &object.Integer{Value: leftVal / rightVal}
-	case "<":
-		return nativeBoolToBooleanObject(leftVal < rightVal)
-	case ">":
-		return nativeBoolToBooleanObject(leftVal > rightVal)
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
-	default:
-		return createError("Operador desconocido: %s %s %s",
-			left.Type(), operator, right.Type())
-	}
-}
-
 func evalPrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
@@ -287,16 +349,7 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 }
 
 func evalExclExpression(right object.Object) object.Object {
-	switch right {
-	case TRUE:
-		return FALSE
-	case FALSE:
-		return TRUE
-	case NULL:
-		return TRUE
-	default:
-		return FALSE
-	}
+	return nativeBoolToBooleanObject(!isTruthy(right))
 }
 
 func nativeBoolToBooleanObject(input bool) *object.Bool {
@@ -355,15 +408,38 @@ func evalVariable(
 	node *ast.Variable,
 	env *object.Environment,
 ) object.Object {
+	if node.Resolved {
+		if val, ok := env.GetAt(node.Depth, node.Slot); ok {
+			return val
+		}
+		// El slot sigue vacio si su enchanted no llego a correr, por
+		// ejemplo en una rama que no se tomo: se busca por nombre.
+	}
 	if val, ok := env.Get(node.Value); ok {
 		return val
 	}
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
+	if constant, ok := mathConstants[node.Value]; ok {
+		return constant
+	}
 	return createError("identifier not found: " + node.Value)
 }
 
+// define liga el nombre que declara v, por su slot si el resolver lo marco.
+// Una funcion sin nombre toma el de v.
+func define(env *object.Environment, v *ast.Variable, val object.Object) {
+	if fn, ok := val.(*object.Function); ok && fn.Name == "" {
+		fn.Name = v.Value
+	}
+	if v.Resolved {
+		env.SetAt(v.Slot, v.Value, val)
+	} else {
+		env.Set(v.Value, val)
+	}
+}
+
 func evalExpressions(
 	exps []ast.Expression,
 	env *object.Environment,
@@ -380,14 +456,59 @@ func evalExpressions(
 
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// ApplyFunction llama a fn con args igual que una llamada del programa y
+// devuelve su resultado, o un *object.Error. Los builtins usan rt.
+func ApplyFunction(fn object.Object, args []object.Object, rt *object.Runtime) object.Object {
+	return callFunction(fn, args, rt, "")
+}
+
+// callFunction anota la llamada en la pila de rt mientras corre fn, y si da
+// un error que todavia no tiene pila le copia la de ese momento.
+func callFunction(fn object.Object, args []object.Object, rt *object.Runtime, position string) object.Object {
+	rt.PushFrame(object.Frame{Function: functionName(fn), Position: position})
+	result := applyFunction(fn, args, rt)
+	if errObj, ok := result.(*object.Error); ok && errObj.Stack == nil {
+		errObj.Stack = rt.Trace()
+	}
+	rt.PopFrame()
+	return result
+}
+
+func functionName(fn object.Object) string {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if fn.Name != "" {
+			return fn.Name
+		}
+		return "isme"
+	case *object.Builtin:
+		return fn.Name
+	}
+	return string(fn.Type())
+}
+
+func applyFunction(fn object.Object, args []object.Object, rt *object.Runtime) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
+		if err := rt.Enter(); err != nil {
+			return err
+		}
+		defer rt.Leave()
+		extendedEnv, err := extendFunctionEnv(fn, args)
+		if err != nil {
+			return err
+		}
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
-		return fn.Fn(args...)
+		if err := checkArguments(fn, args); err != nil {
+			return err
+		}
+		result := fn.Fn(rt, args...)
+		if err := rt.Allocate(result); err != nil {
+			return err
+		}
+		return result
 	default:
 		return createError("No es una funcion, sino: %s", fn.Type())
 	}
@@ -396,12 +517,18 @@ func applyFunction(fn object.Object, args []object.Object) object.Object {
 func extendFunctionEnv(
 	fn *object.Function,
 	args []object.Object,
-) *object.Environment {
+) (*object.Environment, *object.Error) {
+	if len(args) != len(fn.Parameters) {
+		return nil, createError("Numero equivocado de argumentos. Son: %d, deberian ser %d",
+			len(args), len(fn.Parameters))
+	}
 	env := object.NewEnclosedEnvironment(fn.Env)
 	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		if err := bindPattern(param, args[paramIdx], env); err != nil {
+			return nil, err
+		}
 	}
-	return env
+	return env, nil
 }
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnVal); ok {
@@ -410,12 +537,43 @@ func unwrapReturnValue(obj object.Object) object.Object {
 	return obj
 }
 
+// evalIndexChain evalua un indice y dice si la cadena de indices a la que
+// pertenece se corto en un ?[ sobre null. Cortada, el resto de la cadena
+// tambien es null: h?["a"]["b"] con h nulo no intenta ["b"] sobre null.
+func evalIndexChain(node *ast.IndexExpression, env *object.Environment) (object.Object, bool) {
+	var left object.Object
+	skipped := false
+	if inner, ok := node.Left.(*ast.IndexExpression); ok {
+		// Lo mismo que hace Eval con el indice de adentro.
+		if err := env.Runtime().Step(); err != nil {
+			return err, false
+		}
+		left, skipped = evalIndexChain(inner, env)
+		left = locate(inner, left)
+	} else {
+		left = Eval(node.Left, env)
+	}
+	if isError(left) {
+		return left, false
+	}
+	if skipped || node.Optional && left == NULL {
+		return NULL, true
+	}
+	index := Eval(node.Index, env)
+	if isError(index) {
+		return index, false
+	}
+	return evalIndexExpression(left, index), false
+}
+
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
-	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+	case left.Type() == object.ARRAY_OBJ && isInteger(index):
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.MODULE_OBJ:
+		return evalModuleIndexExpression(left, index)
 	default:
 		return createError("index operator not supported: %s", left.Type())
 	}
@@ -423,7 +581,12 @@ func evalIndexExpression(left, index object.Object) object.Object {
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
+	integer, ok := index.(*object.Integer)
+	if !ok {
+		// Un BigInt no cabe en un int64, asi que siempre queda fuera.
+		return NULL
+	}
+	idx := integer.Value
 	max := int64(len(arrayObject.Elements) - 1)
 	if idx < 0 || idx > max {
 		return NULL
@@ -435,9 +598,9 @@ func evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
 ) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	hash := object.NewHash()
+	for _, pairNode := range node.Pairs {
+		key := Eval(pairNode.Key, env)
 		if isError(key) {
 			return key
 		}
@@ -445,14 +608,13 @@ func evalHashLiteral(
 		if !ok {
 			return createError("No se puede usar este tipo para llave de hashMap: %s", key.Type())
 		}
-		value := Eval(valueNode, env)
+		value := Eval(pairNode.Value, env)
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 func evalHashIndexExpression(hash, index object.Object) object.Object {
@@ -461,42 +623,50 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 	if !ok {
 		return createError("unusable as hash key: %s", index.Type())
 	}
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Get(key.HashKey())
 	if !ok {
 		return NULL
 	}
 	return pair.Value
 }
 
+// BuiltinNames devuelve los nombres predefinidos, funciones y constantes, en
+// orden.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins)+len(mathConstants))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	for name := range mathConstants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// arrayParam es el parametro de los builtins que trabajan sobre arrays.
+var arrayParam = object.Param{Name: "arr", Types: []object.ObjectType{object.ARRAY_OBJ}}
+
 var builtins = map[string]*object.Builtin{
 	"len": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
-					len(args))
-			}
+		Name: "len",
+		Signature: object.Signature{Params: []object.Param{
+			{Name: "valor", Types: []object.ObjectType{object.STRING_OBJ, object.ARRAY_OBJ}},
+		}, Type: "isme(any) int"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			switch arg := args[0].(type) {
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
-			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
 			default:
-				return createError("Tipo sin soporte para `len` no es string sino: %s",
-					args[0].Type())
+				return &object.Integer{Value: int64(len(arg.(*object.String).Value))}
 			}
 		},
 	},
 
 	"debut": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
-					len(args))
-			}
-			if args[0].Type() != object.ARRAY_OBJ {
-				return createError("Tipo sin soporte para `debut` no es array sino: %s",
-					args[0].Type())
-			}
+		Name:      "debut",
+		Signature: object.Signature{Params: []object.Param{arrayParam}, Type: "isme([a]) a"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			arr := args[0].(*object.Array)
 			if len(arr.Elements) > 0 {
 				return arr.Elements[0]
@@ -506,15 +676,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"ttpd": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
-					len(args))
-			}
-			if args[0].Type() != object.ARRAY_OBJ {
-				return createError("Tipo sin soporte para `ttpd` no es array sino: %s",
-					args[0].Type())
-			}
+		Name:      "ttpd",
+		Signature: object.Signature{Params: []object.Param{arrayParam}, Type: "isme([a]) a"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 			if length > 0 {
@@ -525,15 +689,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"rest": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
-					len(args))
-			}
-			if args[0].Type() != object.ARRAY_OBJ {
-				return createError("Tipo sin soporte para `ttpd` no es array sino: %s",
-					args[0].Type())
-			}
+		Name:      "rest",
+		Signature: object.Signature{Params: []object.Param{arrayParam}, Type: "isme([a]) [a]"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 			if length > 0 {
@@ -545,15 +703,9 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"billboard": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 2 {
-				return createError("Numero equivocado de argumentos. Son: %d, deberian ser 1",
-					len(args))
-			}
-			if args[0].Type() != object.ARRAY_OBJ {
-				return createError("Tipo sin soporte para `ttpd` no es array sino: %s",
-					args[0].Type())
-			}
+		Name:      "billboard",
+		Signature: object.Signature{Params: []object.Param{arrayParam, {Name: "valor"}}, Type: "isme([a], a) [a]"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 			newElements := make([]object.Object, length+1, length+1)
@@ -563,11 +715,64 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"SpeakNow": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Name:      "SpeakNow",
+		Signature: object.Signature{Params: []object.Param{{Name: "valores"}}, Variadic: true, Type: "isme(any) null"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			for _, arg := range args {
-				fmt.Println(arg.Inspect())
+				fmt.Fprintln(rt.Stdout, arg.Inspect())
 			}
 			return NULL
 		},
 	},
+	"input": &object.Builtin{
+		Name:      "input",
+		Signature: object.Signature{Type: "isme() string"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			line, err := rt.ReadLine()
+			if err == io.EOF {
+				return NULL
+			}
+			if err != nil {
+				return createError("No se pudo leer la entrada: %s", err)
+			}
+			return &object.String{Value: line}
+		},
+	},
+}
+
+// Las bibliotecas de builtins que viven en otros archivos se agregan a
+// builtins al iniciar el paquete.
+func init() {
+	for _, library := range [][]*object.Builtin{stringBuiltins, mathBuiltins, collectionBuiltins, hashBuiltins} {
+		for _, builtin := range library {
+			builtins[builtin.Name] = builtin
+		}
+	}
+}
+
+// Predeclared devuelve los builtins y las constantes por nombre, para las
+// pasadas que necesitan sus firmas o sus valores, como el typechecker.
+func Predeclared() map[string]object.Object {
+	values := make(map[string]object.Object, len(builtins)+len(mathConstants))
+	for name, builtin := range builtins {
+		values[name] = builtin
+	}
+	for name, constant := range mathConstants {
+		values[name] = constant
+	}
+	return values
+}
+
+// checkArguments valida args contra la firma del builtin, para que ningun
+// builtin tenga que revisarlos y todos den los mismos mensajes.
+func checkArguments(fn *object.Builtin, args []object.Object) *object.Error {
+	if msg := fn.ArityError(len(args)); msg != "" {
+		return createError("%s", msg)
+	}
+	for i, arg := range args {
+		if msg := fn.TypeError(i, arg.Type()); msg != "" {
+			return createError("%s", msg)
+		}
+	}
+	return nil
 }
@@ -1,10 +1,15 @@
 package evaluator
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"main/lexer"
 	"main/object"
 	"main/parser"
+	"main/resolver"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +40,9 @@ func testEval(input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
 	program := p.ParseProgram()
+	// Sin revisar los errores del resolver: los tests de errores en tiempo
+	// de ejecucion necesitan llegar al evaluador.
+	resolver.New().Resolve(program)
 	env := object.NewEnvironment()
 	return Eval(program, env)
 }
@@ -99,6 +107,97 @@ func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
 	return true
 }
 
+func TestIntegerOverflowPromotesToBigInt(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"9223372036854775807 * 9223372036854775807", "85070591730234615847396907784232501249"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+		{"enchanted x = 9223372036854775807 * 4; -x", "-36893488147419103228"},
+		{"(9223372036854775807 + 1) / 3", "3074457345618258602"},
+		{"9223372036854775808", "9223372036854775808"},
+		{"-9223372036854775808", "-9223372036854775808"},
+		{"100000000000000000000 - 1", "99999999999999999999"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%q: resultado erroneo. esperado=%s, obtenido=%s",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestBigIntBackToInteger(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"9223372036854775807 + 1 - 1", 9223372036854775807},
+		{"(9223372036854775807 * 2) / 2", 9223372036854775807},
+		{"enchanted big = 9223372036854775807 + 10; big - big", 0},
+		{"9223372036854775808 - 1", 9223372036854775807},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBigIntComparisons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"9223372036854775807 + 1 > 9223372036854775807", true},
+		{"9223372036854775807 < 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 == 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 != 1", true},
+		{"9223372036854775807 + 1 == 1.0", false},
+		{"(9223372036854775807 + 1) * 1.0 > 1.0", true},
+	}
+
+	for _, tt := range tests {
+		testBoolObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	tests := []string{
+		"1 / 0",
+		"enchanted cero = 0; 10 / cero",
+		"(9223372036854775807 + 1) / 0",
+		"1.5 / 0",
+		"1 / 0.0",
+	}
+
+	for _, input := range tests {
+		errObj, ok := testEval(input).(*object.Error)
+		if !ok {
+			t.Errorf("%q: se esperaba un error", input)
+			continue
+		}
+		if errObj.Message != "Division por cero" {
+			t.Errorf("%q: mensaje erroneo: %q", input, errObj.Message)
+		}
+	}
+}
+
+func TestBigIntHashKeys(t *testing.T) {
+	input := `enchanted k = 9223372036854775807 + 1;
+enchanted h = {k: "grande", 1: "chico"};
+h[9223372036854775807 + 1]`
+	str, ok := testEval(input).(*object.String)
+	if !ok || str.Value != "grande" {
+		t.Fatalf("resultado erroneo: %v", testEval(input))
+	}
+}
+
 func TestEvalBoolExpression(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -187,3 +286,677 @@ func testNullObject(t *testing.T, obj object.Object) bool {
 	}
 	return true
 }
+
+func TestNullLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"BlankSpace", nil},
+		{"BlankSpace == BlankSpace", true},
+		{"BlankSpace != BlankSpace", false},
+		{"5 == BlankSpace", false},
+		{"2.5 != BlankSpace", true},
+		{"[1, 2][5] == BlankSpace", true},
+		{"[1, 2][9223372036854775807 + 1] == BlankSpace", true},
+		{"[1, 2][-9223372036854775809] == BlankSpace", true},
+		{"!BlankSpace", true},
+		{"!!BlankSpace", false},
+		{"LoverEra (BlankSpace) { 1 } RepEra { 2 }", 2},
+		{"LoverEra (0) { 1 } RepEra { 2 }", 1},
+		{`LoverEra ("") { 1 } RepEra { 2 }`, 1},
+		{"LoverEra (!BadBlood) { 1 } RepEra { 2 }", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBoolObject(t, evaluated, expected)
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestNullishAndOptionalIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"BlankSpace ?? 5", 5},
+		{"3 ?? 5", 3},
+		{"BadBlood ?? 5", false},
+		{"[1, 2][9] ?? 7", 7},
+		{`{"a": 1}["b"] ?? 2`, 2},
+		{`{"a": 1}?["a"]`, 1},
+		{`BlankSpace?["a"]`, nil},
+		{`BlankSpace?["a"]?["b"] ?? 4`, 4},
+		{`{"a": {"b": 3}}?["a"]?["b"]`, 3},
+		{`{"a": BlankSpace}?["a"]?["b"]`, nil},
+		{`BlankSpace?["a"]["b"]`, nil},
+		{`BlankSpace?["a"]["b"][0] ?? 6`, 6},
+		{`BlankSpace?["a"][1 / 0]`, nil},
+		{`{"a": {"b": 3}}?["a"]["b"]`, 3},
+		{"1 ?? noDefinida", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBoolObject(t, evaluated, expected)
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"enchanted [a, b] = [1, 2]; a + b", 3},
+		{"enchanted [a, ...rest] = [1, 2, 3]; len(rest)", 2},
+		{"enchanted [a, ...rest] = [1, 2, 3]; rest[1]", 3},
+		{"enchanted [a, ...rest] = [1]; len(rest)", 0},
+		{"enchanted [a, [b, c]] = [1, [2, 3]]; a + b + c", 6},
+		{`enchanted {name, age} = {"name": "taylor", "age": 34}; age`, 34},
+		{`enchanted [{x}, y] = [{"x": 5}, 6]; x * y`, 30},
+		{"enchanted f = isme([a, b]) { a * b }; f([3, 4])", 12},
+		{`enchanted edad = isme({age}) { age }; edad({"age": 20, "name": "t"})`, 20},
+		{"enchanted primero = isme([x, ...xs]) { x }; primero([9, 8, 7])", 9},
+		{"enchanted [a, b] = [1, 2, 3]", "El array tiene 3 elementos, el patron [a, b] necesita 2"},
+		{"enchanted [a, b, ...c] = [1]", "El array tiene 1 elementos, el patron [a, b, ...c] necesita al menos 2"},
+		{"enchanted [a] = 5", "No se puede desestructurar INTEGER como array: [a]"},
+		{`enchanted {a} = {"b": 1}`, `El hashMap no tiene la llave "a" que pide el patron {a}`},
+		{"enchanted {a} = [1]", "No se puede desestructurar ARRAY como hashMap: {a}"},
+		{"enchanted f = isme([a, b]) { a }; f([1])", "El array tiene 1 elementos, el patron [a, b] necesita 2"},
+		{"enchanted f = isme(a, b) { a }; f(1)", "Numero equivocado de argumentos. Son: 1, deberian ser 2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%s: no se retorno un error. Sino: %T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("mensaje erroneo. Esperaba %q, obtuvo %q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len("hola")`, 4},
+		{"len([1, 2, 3])", 3},
+		{"debut([7, 8])", 7},
+		{"ttpd([7, 8])", 8},
+		{"len(rest([7, 8, 9]))", 2},
+		{"ttpd(billboard([1], 2))", 2},
+		{"len(1)", "Tipo sin soporte para `len`: el argumento valor deberia ser STRING o ARRAY, no INTEGER"},
+		{`len("a", "b")`, "Numero equivocado de argumentos para `len`. Son: 2, deberian ser 1"},
+		{"rest(1)", "Tipo sin soporte para `rest`: el argumento arr deberia ser ARRAY, no INTEGER"},
+		{"billboard([1])", "Numero equivocado de argumentos para `billboard`. Son: 1, deberian ser 2"},
+		{"billboard(1, 2)", "Tipo sin soporte para `billboard`: el argumento arr deberia ser ARRAY, no INTEGER"},
+		{"ttpd()", "Numero equivocado de argumentos para `ttpd`. Son: 0, deberian ser 1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("%s: no se retorno un error. Sino: %T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("mensaje erroneo. Esperaba %q, obtuvo %q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a,b,,c", ",")`, "[a, b, , c]"},
+		{`len(split("abc", ""))`, "3"},
+		{`join(["a", "b", "c"], "-")`, "a-b-c"},
+		{`join([], "-")`, ""},
+		{`trim("  hola   ")`, "hola"},
+		{`upper("Hola")`, "HOLA"},
+		{`lower("ÁRBOL")`, "árbol"},
+		{`contains("folklore", "lore")`, "true"},
+		{`contains("folklore", "rep")`, "false"},
+		{`startsWith("evermore", "ever")`, "true"},
+		{`endsWith("evermore", "ever")`, "false"},
+		{`replace("la la la", "la", "lo")`, "lo lo lo"},
+		{`indexOf("canción de amor", "de")`, "8"},
+		{`indexOf("hola", "x")`, "-1"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`repeat("", 4611686018427387904)`, ""},
+		{`chars("año")`, "[a, ñ, o]"},
+		{`parseInt(" 42 ")`, "42"},
+		{`parseInt("-7")`, "-7"},
+		{`parseInt("99999999999999999999")`, "99999999999999999999"},
+		{`parseInt("4x")`, "null"},
+		{`parseInt("4x") ?? 0`, "0"},
+		{`parseFloat("2.5")`, "2.500000"},
+		{`parseFloat("nada")`, "null"},
+		{`format("hola")`, "hola"},
+		{`format("%s tiene %d años", "Ana", 30)`, "Ana tiene 30 años"},
+		{`format("%.2f|%5d|%-4s|", 3.14159, 42, "ab")`, "3.14|   42|ab  |"},
+		{`format("%v %v", [1, 2], SparksFly)`, "[1, 2] true"},
+		{`format("%d", 9223372036854775807 + 1)`, "9223372036854775808"},
+		{`format("%f", 1)`, "1.000000"},
+		{`format("%.1f", 9223372036854775807 * 10)`, "92233720368547758070.0"},
+		{`format("%08.3f|%-6d|", 2.5, 7)`, "0002.500|7     |"},
+		{`format("100%%")`, "100%"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: resultado erroneo. esperado=%q, obtenido=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a")`, "Numero equivocado de argumentos para `split`. Son: 1, deberian ser 2"},
+		{`upper(1)`, "Tipo sin soporte para `upper`: el argumento s deberia ser STRING, no INTEGER"},
+		{`join(["a", 1], ",")`, "Tipo sin soporte para `join`: el elemento 1 deberia ser STRING, no INTEGER"},
+		{`join("ab", ",")`, "Tipo sin soporte para `join`: el argumento arr deberia ser ARRAY, no STRING"},
+		{`repeat("a", 1.5)`, "Tipo sin soporte para `repeat`: el argumento n deberia ser INTEGER, no FLOAT"},
+		{`repeat("a", -1)`, "Valor invalido para `repeat`: n no puede ser negativo, es -1"},
+		{`repeat("ab", 4611686018427387904)`, "Valor invalido para `repeat`: el resultado tendria mas de 268435456 bytes"},
+		{`repeat("a", 268435457)`, "Valor invalido para `repeat`: el resultado tendria mas de 268435456 bytes"},
+		{`format()`, "Numero equivocado de argumentos para `format`. Son: 0, deberian ser al menos 1"},
+		{`format(1)`, "Tipo sin soporte para `format`: el argumento formato deberia ser STRING, no INTEGER"},
+		{`format("%d")`, "Formato invalido para `format`: falta el valor para %d"},
+		{`format("%s", 1, 2)`, "Formato invalido para `format`: sobran 1 valores"},
+		{`format("%q", 1)`, "Formato invalido para `format`: verbo desconocido %q"},
+		{`format("50%")`, "Formato invalido para `format`: % no tiene verbo"},
+		{`format("%5.2.3f", 1.0)`, "Formato invalido para `format`: %5.2.3f tiene el ancho o la precision mal escritos"},
+		{`format("%.5.f", 1.0)`, "Formato invalido para `format`: %.5.f tiene el ancho o la precision mal escritos"},
+		{`format("%-5.")`, "Formato invalido para `format`: %-5. no tiene verbo"},
+		{`format("%d", "x")`, "Tipo sin soporte para `format`: %d no acepta STRING"},
+		{`format("%f", "x")`, "Tipo sin soporte para `format`: %f no acepta STRING"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no se retorno un error", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: mensaje erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abs(-3)", "3"},
+		{"abs(3)", "3"},
+		{"abs(-2.5)", "2.500000"},
+		{"abs(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"min(3, 1, 2)", "1"},
+		{"max(3, 1, 2)", "3"},
+		{"min(3)", "3"},
+		{"max(1, 2.5)", "2.500000"},
+		{"min(1, 2.5)", "1.000000"},
+		{"max(1, 9223372036854775807 + 1)", "9223372036854775808"},
+		{"floor(2.7)", "2"},
+		{"floor(-2.5)", "-3"},
+		{"ceil(2.1)", "3"},
+		{"round(2.5)", "3"},
+		{"round(-2.5)", "-3"},
+		{"round(7)", "7"},
+		{"floor(10000000000.0 * 10000000000.0)", "100000000000000000000"},
+		{"sqrt(16)", "4.000000"},
+		{"sqrt(2.25)", "1.500000"},
+		{"pow(2, 10)", "1024.000000"},
+		{"pow(4, 0.5)", "2.000000"},
+		{"sin(0)", "0.000000"},
+		{"cos(0)", "1.000000"},
+		{"tan(0)", "0.000000"},
+		{"atan(1) * 4 == PI", "true"},
+		{"log(E)", "1.000000"},
+		{"exp(0)", "1.000000"},
+		{"round(PI * 100)", "314"},
+		{"enchanted PI = 3; PI", "3"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: resultado erroneo. esperado=%q, obtenido=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMathBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`sqrt("4")`, "Tipo sin soporte para `sqrt`: el argumento x deberia ser INTEGER o BIGINT o FLOAT, no STRING"},
+		{"sqrt(-1)", "Valor invalido para `sqrt`: el resultado no es un numero finito"},
+		{"log(0)", "Valor invalido para `log`: el resultado no es un numero finito"},
+		{"pow(10, 400)", "Valor invalido para `pow`: el resultado no es un numero finito"},
+		{"min()", "Numero equivocado de argumentos para `min`. Son: 0, deberian ser al menos 1"},
+		{"max(1, BlankSpace)", "Tipo sin soporte para `max`: el argumento valores deberia ser INTEGER o BIGINT o FLOAT, no NULL"},
+		{"randomInt(0)", "Valor invalido para `randomInt`: n deberia ser positivo, es 0"},
+		{"seed(1.5)", "Tipo sin soporte para `seed`: el argumento n deberia ser INTEGER, no FLOAT"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no se retorno un error", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: mensaje erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestRandomIsSeedable(t *testing.T) {
+	input := `seed(42); [random(), randomInt(100), randomInt(100)]`
+	first, second := testEval(input).Inspect(), testEval(input).Inspect()
+	if first != second {
+		t.Errorf("la misma semilla dio secuencias distintas: %s y %s", first, second)
+	}
+
+	values := testEval(`seed(7); [random(), randomInt(3)]`).(*object.Array).Elements
+	if f := values[0].(*object.Float).Value; f < 0 || f >= 1 {
+		t.Errorf("random fuera de [0, 1): %f", f)
+	}
+	if n := values[1].(*object.Integer).Value; n < 0 || n >= 3 {
+		t.Errorf("randomInt(3) fuera de [0, 3): %d", n)
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], isme(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([], isme(x) { x * 2 })", "[]"},
+		{`map(["a", "b"], upper)`, "[A, B]"},
+		{"filter([1, 2, 3, 4], isme(x) { x > 2 })", "[3, 4]"},
+		{"filter([1, 2], isme(x) { BlankSpace })", "[]"},
+		{"reduce([1, 2, 3, 4], isme(acc, x) { acc + x }, 0)", "10"},
+		{"reduce([], isme(acc, x) { acc + x }, 7)", "7"},
+		{"find([1, 5, 8, 9], isme(x) { x > 4 })", "5"},
+		{"find([1, 2], isme(x) { x > 4 })", "null"},
+		{"any([1, 2, 3], isme(x) { x == 2 })", "true"},
+		{"any([], isme(x) { SparksFly })", "false"},
+		{"all([1, 2, 3], isme(x) { x > 0 })", "true"},
+		{"all([1, -2, 3], isme(x) { x > 0 })", "false"},
+		{"all([], isme(x) { BadBlood })", "true"},
+		{"sort([3, 1.5, 2, -1])", "[-1, 1.500000, 2, 3]"},
+		{`sort(["pera", "banana", "uva"])`, "[banana, pera, uva]"},
+		{"sortBy([3, 1, 2], isme(a, b) { a > b })", "[3, 2, 1]"},
+		{"sortBy([[2, 1], [1, 2], [2, 0]], isme(a, b) { debut(a) < debut(b) })", "[[1, 2], [2, 1], [2, 0]]"},
+		{"enchanted xs = [2, 1]; sort(xs); xs", "[2, 1]"},
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{`enumerate(["a", "b"])`, "[[0, a], [1, b]]"},
+		{"enchanted factor = 3; map([1, 2], isme(x) { x * factor })", "[3, 6]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: resultado erroneo. esperado=%q, obtenido=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestCollectionBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1], 2)", "Tipo sin soporte para `map`: el argumento fn deberia ser FUNCTION o BUILTIN, no INTEGER"},
+		{"map(1, isme(x) { x })", "Tipo sin soporte para `map`: el argumento arr deberia ser ARRAY, no INTEGER"},
+		{"reduce([1], isme(acc, x) { acc + x })", "Numero equivocado de argumentos para `reduce`. Son: 2, deberian ser 3"},
+		{"map([1], isme(a, b) { a })", "Numero equivocado de argumentos. Son: 1, deberian ser 2"},
+		{`map([1, "a", 3], isme(x) { x + 1 })`, "Error de tipos: STRING + INTEGER"},
+		{`sort([1, "a"])`, "Tipo sin soporte para `sort`: no se puede comparar STRING con INTEGER"},
+		{`sortBy([1, 2, 3], isme(a, b) { a + "x" })`, "Error de tipos: INTEGER + STRING"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no se retorno un error", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: mensaje erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestCallbacksShareTheRuntime(t *testing.T) {
+	program := parser.New(lexer.New(
+		"enchanted doble = isme(x) { debut(x) }; map([[1], 2], doble)")).ParseProgram()
+	errObj, ok := Eval(program, object.NewEnvironment()).(*object.Error)
+	if !ok {
+		t.Fatalf("se esperaba un error")
+	}
+	var frames []string
+	for _, frame := range errObj.Stack {
+		frames = append(frames, frame.Function)
+	}
+	if got := strings.Join(frames, " < "); got != "debut < doble < map" {
+		t.Errorf("pila erronea: %s", got)
+	}
+
+	env := object.NewEnvironment()
+	env.Runtime().Limits = object.Limits{MaxSteps: 200}
+	program = parser.New(lexer.New(
+		"enchanted f = isme(x) { f(x) }; map([1], f)")).ParseProgram()
+	errObj, ok = EvalContext(context.Background(), program, env).(*object.Error)
+	if !ok || errObj.Kind != object.StepLimitError {
+		t.Errorf("se esperaba que el callback cuente para el limite de pasos. obtuvo=%v", errObj)
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({"b": 1, "a": 2, 3: 3})`, "[b, a, 3]"},
+		{`values({"b": 1, "a": 2})`, "[1, 2]"},
+		{`entries({"b": 1, SparksFly: 2})`, "[[b, 1], [true, 2]]"},
+		{"keys({})", "[]"},
+		{`has({"a": 1}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`delete({"a": 1, "b": 2, "c": 3}, "b")`, "{a: 1, c: 3}"},
+		{`delete({"a": 1}, "z")`, "{a: 1}"},
+		{`enchanted h = {"a": 1}; delete(h, "a"); h`, "{a: 1}"},
+		{`merge({"a": 1, "b": 2}, {"c": 3, "a": 4})`, "{a: 4, b: 2, c: 3}"},
+		{`merge({"a": 1}, {"b": 2}, {"a": 3})`, "{a: 3, b: 2}"},
+		{`merge({"a": 1})`, "{a: 1}"},
+		{`enchanted h = {"a": 1}; merge(h, {"a": 2}); h`, "{a: 1}"},
+		{`size({"a": 1, "b": 2})`, "2"},
+		{`size(delete({"a": 1}, "a"))`, "0"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: resultado erroneo. esperado=%q, obtenido=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"keys([1])", "Tipo sin soporte para `keys`: el argumento hash deberia ser HASH, no ARRAY"},
+		{`has({"a": 1}, [1])`, "No se puede usar este tipo para llave de hashMap: ARRAY"},
+		{`has({1: 1}, 1.0)`, "No se puede usar este tipo para llave de hashMap: FLOAT"},
+		{`delete({"a": 1}, isme() { 1 })`, "No se puede usar este tipo para llave de hashMap: FUNCTION"},
+		{"merge()", "Numero equivocado de argumentos para `merge`. Son: 0, deberian ser al menos 1"},
+		{`merge({"a": 1}, 2)`, "Tipo sin soporte para `merge`: el argumento hashes deberia ser HASH, no INTEGER"},
+		{`size({"a": 1}, 2)`, "Numero equivocado de argumentos para `size`. Son: 2, deberian ser 1"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no se retorno un error", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: mensaje erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestStreams(t *testing.T) {
+	program := parser.New(lexer.New(
+		`enchanted nombre = input(); SpeakNow("hola", nombre); [input(), input()]`)).ParseProgram()
+	env := object.NewEnvironment()
+	var out bytes.Buffer
+	env.Runtime().Stdout = &out
+	env.Runtime().SetStdin(strings.NewReader("Taylor\r\n13"))
+
+	result := Eval(program, env)
+	if out.String() != "hola\nTaylor\n" {
+		t.Errorf("salida erronea. obtuvo=%q", out.String())
+	}
+	// La ultima linea no necesita salto, y al final de la entrada input da
+	// BlankSpace.
+	if result.Inspect() != "[13, null]" {
+		t.Errorf("resultado erroneo. obtuvo=%s", result.Inspect())
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	const (
+		forever = "enchanted f = isme(n) { f(n + 1) }; f(0)"
+		depth   = "enchanted f = isme(n) { LoverEra (n == 0) { 0 } RepEra { f(n - 1) } };"
+		grow    = "enchanted f = isme(a, n) { LoverEra (n == 0) { a } RepEra { f(billboard(a, n), n - 1) } };"
+	)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		input    string
+		ctx      context.Context
+		limits   object.Limits
+		kind     object.ErrorKind
+		expected string
+	}{
+		{forever, nil, object.Limits{}, object.DepthLimitError,
+			"Se supero la profundidad maxima de 10000 llamadas"},
+		{depth + "f(20)", nil, object.Limits{MaxDepth: 10}, object.DepthLimitError,
+			"Se supero la profundidad maxima de 10 llamadas"},
+		{depth + "f(5)", nil, object.Limits{MaxDepth: 10}, object.ProgramError, ""},
+		{depth + "f(50)", nil, object.Limits{MaxSteps: 100}, object.StepLimitError,
+			"Se supero el limite de 100 pasos"},
+		{grow + "len(f([], 100))", nil, object.Limits{MaxAllocations: 1000}, object.AllocationLimitError,
+			"Se supero el limite de 1000 objetos"},
+		{grow + "len(f([], 10))", nil, object.Limits{MaxAllocations: 1000}, object.ProgramError, ""},
+		{`len(repeat("a", 100000000))`, nil, object.Limits{MaxAllocations: 100}, object.AllocationLimitError,
+			"Se supero el limite de 100 objetos"},
+		{`len(repeat("a", 1000))`, nil, object.Limits{MaxAllocations: 100}, object.ProgramError, ""},
+		{forever, canceled, object.Limits{}, object.CanceledError,
+			"Ejecucion cancelada: context canceled"},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		env := object.NewEnvironment()
+		env.Runtime().Limits = tt.limits
+
+		result := EvalContext(tt.ctx, program, env)
+		errObj, ok := result.(*object.Error)
+		if tt.expected == "" {
+			if ok {
+				t.Errorf("%q: error inesperado: %s", tt.input, errObj.Message)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%q: se esperaba un error. obtuvo=%T (%+v)", tt.input, result, result)
+			continue
+		}
+		if errObj.Kind != tt.kind || errObj.Message != tt.expected {
+			t.Errorf("%q: error erroneo. esperado=%d %q, obtenido=%d %q",
+				tt.input, tt.kind, tt.expected, errObj.Kind, errObj.Message)
+		}
+	}
+}
+
+func TestBudgetsStartOverEachRun(t *testing.T) {
+	program := parser.New(lexer.New("1 + 2 + 3")).ParseProgram()
+	env := object.NewEnvironment()
+	env.Runtime().Limits = object.Limits{MaxSteps: 10}
+	for i := 0; i < 3; i++ {
+		testIntegerObject(t, EvalContext(context.Background(), program, env), 6)
+	}
+}
+
+func TestStackTraces(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []object.Frame
+	}{
+		{
+			"enchanted cuenta = isme(n) {\n  LoverEra (n == 0) { y } RepEra { cuenta(n - 1) }\n};\ncuenta(2)",
+			[]object.Frame{{Function: "cuenta", Position: "2:42"}, {Function: "cuenta", Position: "2:42"}, {Function: "cuenta", Position: "4:7"}},
+		},
+		{"enchanted f = isme() { len(1) }; f()", []object.Frame{{Function: "len", Position: "1:27"}, {Function: "f", Position: "1:35"}}},
+		{"isme() { y }()", []object.Frame{{Function: "isme", Position: "1:13"}}},
+		{"enchanted f = isme() { y }; enchanted g = f; g()", []object.Frame{{Function: "f", Position: "1:47"}}},
+		{"y", nil},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q: se esperaba un error", tt.input)
+			continue
+		}
+		if fmt.Sprint(errObj.Stack) != fmt.Sprint(tt.expected) {
+			t.Errorf("%q: pila erronea. esperada=%v, obtenida=%v", tt.input, tt.expected, errObj.Stack)
+		}
+	}
+}
+
+func TestTraceback(t *testing.T) {
+	errObj := testEval("enchanted f = isme() { y }; isme() { f() }()").(*object.Error)
+	expected := "ERROR: 1:24: identifier not found: y\n\ten f, llamada en 1:39\n\ten isme, llamada en 1:43"
+	if errObj.Traceback() != expected {
+		t.Errorf("traza erronea. esperada=%q, obtenida=%q", expected, errObj.Traceback())
+	}
+
+	// Un error fuera de toda funcion tambien dice donde ocurrio.
+	errObj = testEval("enchanted a = 1;\na + SparksFly").(*object.Error)
+	if errObj.Position != "2:3" {
+		t.Errorf("posicion erronea. esperada=%q, obtenida=%q", "2:3", errObj.Position)
+	}
+
+	// En una recursion muy profunda se muestran solo las puntas.
+	program := parser.New(lexer.New("enchanted f = isme() { f() }; f()")).ParseProgram()
+	env := object.NewEnvironment()
+	env.Runtime().Limits = object.Limits{MaxDepth: 25}
+	errObj = Eval(program, env).(*object.Error)
+	lines := strings.Split(errObj.Traceback(), "\n")
+	if len(lines) != 22 || lines[11] != "\t... 6 llamadas mas" || lines[21] != "\ten f, llamada en 1:32" {
+		t.Errorf("traza recortada erronea:\n%s", errObj.Traceback())
+	}
+}
+
+func TestHashLiteralsKeepInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"zeta": 1, "alfa": 2, "beta": 3}`, "{zeta: 1, alfa: 2, beta: 3}"},
+		{`{3: "c", 1: "a", 2: "b"}`, "{3: c, 1: a, 2: b}"},
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}"},
+		{`{BadBlood: 0, SparksFly: 1, "x": [1, 2]}`, "{false: 0, true: 1, x: [1, 2]}"},
+		{`{}`, "{}"},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			evaluated := testEval(tt.input)
+			hash, ok := evaluated.(*object.Hash)
+			if !ok {
+				t.Fatalf("obj no es un Hash. Sino: %T (%+v)", evaluated, evaluated)
+			}
+			if hash.Inspect() != tt.expected {
+				t.Fatalf("Inspect erroneo. Esperaba %q, obtuvo %q", tt.expected, hash.Inspect())
+			}
+		}
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`enchanted key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{SparksFly: 5}[SparksFly]`, 5},
+		{`{"a": 1, "b": 2, "a": 3}["a"]`, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestResolvedVariables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"enchanted a = 5; enchanted b = a * 2; b + a", 15},
+		{"enchanted adder = isme(x) { isme(y) { x + y } }; enchanted addTwo = adder(2); addTwo(3)", 5},
+		{"enchanted x = 1; enchanted f = isme(x) { x * 10 }; f(4) + x", 41},
+		{"enchanted fact = isme(n) { LoverEra (n < 2) { 1 } RepEra { n * fact(n - 1) } }; fact(5)", 120},
+		{"enchanted f = isme() { g() }; enchanted g = isme() { 7 }; f()", 7},
+		// La rama no corre: el slot local queda vacio y se usa el de afuera.
+		{"enchanted x = 1; enchanted f = isme() { LoverEra (BadBlood) { enchanted x = 2 }; x }; f()", 1},
+		{"enchanted x = 1; enchanted f = isme() { LoverEra (SparksFly) { enchanted x = 2 }; x }; f()", 2},
+		{"enchanted f = isme([a, ...b], {c}) { a + len(b) + c }; f([1, 2, 3], {\"c\": 10})", 13},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), int64(tt.expected.(int)))
+	}
+}
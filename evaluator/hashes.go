@@ -0,0 +1,103 @@
+package evaluator
+
+import "main/object"
+
+// Biblioteca de hashMaps. Como los valores no se pueden modificar, delete y
+// merge devuelven un hashMap nuevo. Todo recorre las llaves en el orden en
+// que se insertaron, asi que el resultado es el mismo en cada ejecucion.
+
+var hashParam = object.Param{Name: "hash", Types: []object.ObjectType{object.HASH_OBJ}}
+
+// hashKeyOf devuelve la llave de key, o un error si key no puede ser llave.
+func hashKeyOf(key object.Object) (object.HashKey, *object.Error) {
+	hashable, ok := key.(object.Hashable)
+	if !ok {
+		return object.HashKey{}, createError("No se puede usar este tipo para llave de hashMap: %s", key.Type())
+	}
+	return hashable.HashKey(), nil
+}
+
+func copyHash(hash *object.Hash) *object.Hash {
+	result := object.NewHash()
+	for _, key := range hash.Order {
+		result.Set(key, hash.Pairs[key])
+	}
+	return result
+}
+
+// pairsBuiltin arma keys, values y entries, que convierten cada par en un
+// elemento de un array.
+func pairsBuiltin(name, typ string, element func(pair object.HashPair) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Name:      name,
+		Signature: object.Signature{Params: []object.Param{hashParam}, Type: typ},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			pairs := args[0].(*object.Hash).Ordered()
+			elements := make([]object.Object, len(pairs))
+			for i, pair := range pairs {
+				elements[i] = element(pair)
+			}
+			return &object.Array{Elements: elements}
+		},
+	}
+}
+
+var hashBuiltins = []*object.Builtin{
+	pairsBuiltin("keys", "isme({k: v}) [k]", func(pair object.HashPair) object.Object { return pair.Key }),
+	pairsBuiltin("values", "isme({k: v}) [v]", func(pair object.HashPair) object.Object { return pair.Value }),
+	pairsBuiltin("entries", "isme({k: v}) [[any]]", func(pair object.HashPair) object.Object {
+		return &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}),
+	{
+		Name:      "has",
+		Signature: object.Signature{Params: []object.Param{hashParam, {Name: "llave"}}, Type: "isme({k: v}, k) bool"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			key, err := hashKeyOf(args[1])
+			if err != nil {
+				return err
+			}
+			_, ok := args[0].(*object.Hash).Get(key)
+			return nativeBoolToBooleanObject(ok)
+		},
+	},
+	{
+		Name:      "delete",
+		Signature: object.Signature{Params: []object.Param{hashParam, {Name: "llave"}}, Type: "isme({k: v}, k) {k: v}"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			key, err := hashKeyOf(args[1])
+			if err != nil {
+				return err
+			}
+			result := copyHash(args[0].(*object.Hash))
+			result.Delete(key)
+			return result
+		},
+	},
+	{
+		// merge junta los hashMaps de izquierda a derecha: si una llave se
+		// repite gana el ultimo valor, pero se queda en su primera posicion.
+		Name: "merge",
+		Signature: object.Signature{
+			Params:   []object.Param{hashParam, {Name: "hashes", Types: hashParam.Types}},
+			Variadic: true,
+			Type:     "isme({k: v}, {k: v}) {k: v}",
+		},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			result := copyHash(args[0].(*object.Hash))
+			for _, arg := range args[1:] {
+				hash := arg.(*object.Hash)
+				for _, key := range hash.Order {
+					result.Set(key, hash.Pairs[key])
+				}
+			}
+			return result
+		},
+	},
+	{
+		Name:      "size",
+		Signature: object.Signature{Params: []object.Param{hashParam}, Type: "isme({k: v}) int"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return &object.Integer{Value: int64(args[0].(*object.Hash).Len())}
+		},
+	},
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"main/object"
+	"math"
+	"math/big"
+)
+
+const divisionByZero = "Division por cero"
+
+// evalIntegerInfixExpression opera con int64 mientras el resultado entre;
+// si se desborda, repite la operacion con math/big y devuelve un BigInt.
+func evalIntegerInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := left.(*object.Integer).Value
+	rightVal := right.(*object.Integer).Value
+	switch operator {
+	case "+":
+		sum := leftVal + rightVal
+		if (leftVal >= 0) == (rightVal >= 0) && (sum >= 0) != (leftVal >= 0) {
+			break
+		}
+		return &object.Integer{Value: sum}
+	case "-":
+		diff := leftVal - rightVal
+		if (leftVal >= 0) != (rightVal >= 0) && (diff >= 0) != (leftVal >= 0) {
+			break
+		}
+		return &object.Integer{Value: diff}
+	case "*":
+		product := leftVal * rightVal
+		if leftVal != 0 && (product/leftVal != rightVal ||
+			(leftVal == -1 && rightVal == math.MinInt64) ||
+			(rightVal == -1 && leftVal == math.MinInt64)) {
+			break
+		}
+		return &object.Integer{Value: product}
+	case "/":
+		if rightVal == 0 {
+			return createError(divisionByZero)
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			break
+		}
+		return &object.Integer{Value: leftVal / rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return createError("Operador desconocido: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+	return evalBigIntInfixExpression(operator, toBigInt(left), toBigInt(right))
+}
+
+// evalBigIntInfixExpression es la version de evalIntegerInfixExpression
+// para cuando algun operando o el resultado no entra en int64. Como en Go,
+// la division trunca hacia cero.
+func evalBigIntInfixExpression(operator string, leftVal, rightVal *big.Int) object.Object {
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftVal, rightVal))
+	case "/":
+		if rightVal.Sign() == 0 {
+			return createError(divisionByZero)
+		}
+		return normalizeBigInt(new(big.Int).Quo(leftVal, rightVal))
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return createError("Operador desconocido: %s %s %s",
+			integerType(leftVal), operator, integerType(rightVal))
+	}
+}
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+// toBigInt devuelve el valor de un Integer o un BigInt como *big.Int. El de
+// un BigInt se comparte, asi que no hay que modificarlo.
+func toBigInt(obj object.Object) *big.Int {
+	if b, ok := obj.(*object.BigInt); ok {
+		return b.Value
+	}
+	return big.NewInt(obj.(*object.Integer).Value)
+}
+
+// normalizeBigInt devuelve un Integer si n entra en int64 y un BigInt si no.
+func normalizeBigInt(n *big.Int) object.Object {
+	if n.IsInt64() {
+		return &object.Integer{Value: n.Int64()}
+	}
+	return &object.BigInt{Value: n}
+}
+
+func integerType(n *big.Int) object.ObjectType {
+	if n.IsInt64() {
+		return object.INTEGER_OBJ
+	}
+	return object.BIGINT_OBJ
+}
@@ -0,0 +1,153 @@
+package evaluator
+
+import (
+	"fmt"
+	"main/ast"
+	"main/object"
+)
+
+// DefineMacros quita del programa las definiciones `enchanted x = folklore(...)`
+// de primer nivel y las guarda en env para ExpandMacros.
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	definitions := []int{}
+
+	for i, statement := range program.Statements {
+		if isMacroDefinition(statement) {
+			addMacro(statement, env)
+			definitions = append(definitions, i)
+		}
+	}
+
+	for i := len(definitions) - 1; i >= 0; i = i - 1 {
+		definitionIndex := definitions[i]
+		program.Statements = append(
+			program.Statements[:definitionIndex],
+			program.Statements[definitionIndex+1:]...,
+		)
+	}
+}
+
+func isMacroDefinition(node ast.Statement) bool {
+	letStatement, ok := node.(*ast.LetStatement)
+	if !ok || letStatement.Name == nil {
+		return false
+	}
+	_, ok = letStatement.Value.(*ast.MacroLiteral)
+	return ok
+}
+
+func addMacro(stmt ast.Statement, env *object.Environment) {
+	letStatement, _ := stmt.(*ast.LetStatement)
+	macroLiteral, _ := letStatement.Value.(*ast.MacroLiteral)
+
+	macro := &object.Macro{
+		Parameters: macroLiteral.Parameters,
+		Env:        env,
+		Body:       macroLiteral.Body,
+	}
+
+	env.Set(letStatement.Name.Value, macro)
+}
+
+// ExpandMacros reemplaza cada llamada a un macro por el AST que devuelve. Los
+// argumentos llegan al macro como quotes, sin evaluar.
+//
+// Una llamada con otro numero de argumentos, o cuyo macro falla o no
+// devuelve un quote, queda como estaba y su error, con la posicion de la
+// llamada, se devuelve junto al programa.
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, []string) {
+	errors := []string{}
+	errorf := func(call *ast.CallExpression, format string, a ...interface{}) {
+		errors = append(errors, call.Token.Position()+": "+fmt.Sprintf(format, a...))
+	}
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		callExpression, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+
+		macro, ok := isMacroCall(callExpression, env)
+		if !ok {
+			return node
+		}
+		name := callExpression.Function.(*ast.Variable).Value
+		if len(callExpression.Arguments) != len(macro.Parameters) {
+			errorf(callExpression, "Numero equivocado de argumentos para el macro %s. Son: %d, deberian ser %d",
+				name, len(callExpression.Arguments), len(macro.Parameters))
+			return node
+		}
+
+		args := quoteArgs(callExpression)
+		evalEnv := extendMacroEnv(macro, args)
+
+		evaluated := Eval(macro.Body, evalEnv)
+		evaluated = unwrapReturnValue(evaluated)
+
+		switch result := evaluated.(type) {
+		case *object.Quote:
+			return result.Node
+		case *object.Error:
+			errorf(callExpression, "Error en el macro %s: %s", name, result.Message)
+		default:
+			errorf(callExpression, "El macro %s deberia devolver un quote, no %s", name, typeName(evaluated))
+		}
+		return node
+	})
+	return expanded, errors
+}
+
+// typeName es el tipo de obj, contando como NULL el nil de un bloque vacio.
+func typeName(obj object.Object) object.ObjectType {
+	if obj == nil {
+		return object.NULL_OBJ
+	}
+	return obj.Type()
+}
+
+func isMacroCall(
+	exp *ast.CallExpression,
+	env *object.Environment,
+) (*object.Macro, bool) {
+	identifier, ok := exp.Function.(*ast.Variable)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(identifier.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		return nil, false
+	}
+
+	return macro, true
+}
+
+func quoteArgs(exp *ast.CallExpression) []*object.Quote {
+	args := []*object.Quote{}
+
+	for _, a := range exp.Arguments {
+		args = append(args, &object.Quote{Node: a})
+	}
+
+	return args
+}
+
+func extendMacroEnv(
+	macro *object.Macro,
+	args []*object.Quote,
+) *object.Environment {
+	extended := object.NewEnclosedEnvironment(macro.Env)
+
+	for paramIdx, param := range macro.Parameters {
+		if paramIdx < len(args) {
+			extended.Set(param.Value, args[paramIdx])
+		}
+	}
+
+	return extended
+}
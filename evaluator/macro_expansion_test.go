@@ -0,0 +1,182 @@
+package evaluator
+
+import (
+	"main/ast"
+	"main/lexer"
+	"main/object"
+	"main/parser"
+	"testing"
+)
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+	enchanted number = 1;
+	enchanted function = isme(x, y) { x + y };
+	enchanted mymacro = folklore(x, y) { x + y; };
+	`
+
+	env := object.NewEnvironment()
+	program := testParseProgram(input)
+
+	DefineMacros(program, env)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Numero de statements erroneo. Obtuvo: %d", len(program.Statements))
+	}
+
+	_, ok := env.Get("number")
+	if ok {
+		t.Fatalf("number no deberia estar definido")
+	}
+	_, ok = env.Get("function")
+	if ok {
+		t.Fatalf("function no deberia estar definido")
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro no esta en el entorno")
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("el objeto no es un Macro. Obtuvo: %T (%+v)", obj, obj)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("Numero de parametros erroneo. Obtuvo: %d", len(macro.Parameters))
+	}
+
+	if macro.Parameters[0].String() != "x" {
+		t.Fatalf("el parametro no es 'x'. Obtuvo: %q", macro.Parameters[0])
+	}
+	if macro.Parameters[1].String() != "y" {
+		t.Fatalf("el parametro no es 'y'. Obtuvo: %q", macro.Parameters[1])
+	}
+
+	expectedBody := "(x + y)"
+
+	if macro.Body.String() != expectedBody {
+		t.Fatalf("el cuerpo no es %q. Obtuvo: %q", expectedBody, macro.Body.String())
+	}
+}
+
+func testParseProgram(input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	return p.ParseProgram()
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`
+			enchanted infixExpression = folklore() { quote(1 + 2); };
+
+			infixExpression();
+			`,
+			`(1 + 2)`,
+		},
+		{
+			`
+			enchanted reverse = folklore(a, b) { quote(unquote(b) - unquote(a)); };
+
+			reverse(2 + 2, 10 - 5);
+			`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`
+			enchanted unless = folklore(condition, consequence, alternative) {
+				quote(LoverEra (!(unquote(condition))) {
+					unquote(consequence);
+				} RepEra {
+					unquote(alternative);
+				});
+			};
+
+			unless(10 > 5, SpeakNow("not greater"), SpeakNow("greater"));
+			`,
+			`LoverEra (!(10 > 5)) { SpeakNow("not greater") } RepEra { SpeakNow("greater") }`,
+		},
+		{
+			`
+			enchanted twice = folklore(x) { quote(unquote(x) * 2); };
+
+			twice(1);
+			twice(a + b);
+			`,
+			`(1 * 2); ((a + b) * 2)`,
+		},
+	}
+
+	for _, tt := range tests {
+		expected := testParseProgram(tt.expected)
+		program := testParseProgram(tt.input)
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, errors := ExpandMacros(program, env)
+		if len(errors) != 0 {
+			t.Fatalf("errores inesperados: %v", errors)
+		}
+
+		if expanded.String() != expected.String() {
+			t.Errorf("no son iguales. Esperaba: %q, obtuvo: %q",
+				expected.String(), expanded.String())
+		}
+	}
+}
+
+func TestEvalExpandedMacro(t *testing.T) {
+	input := `
+	enchanted unless = folklore(condition, consequence, alternative) {
+		quote(LoverEra (!(unquote(condition))) {
+			unquote(consequence);
+		} RepEra {
+			unquote(alternative);
+		});
+	};
+
+	unless(10 > 5, 1, 2);
+	`
+	program := testParseProgram(input)
+	macroEnv := object.NewEnvironment()
+	DefineMacros(program, macroEnv)
+	expanded, _ := ExpandMacros(program, macroEnv)
+
+	testIntegerObject(t, Eval(expanded, object.NewEnvironment()), 2)
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"enchanted dos = folklore(a, b) { quote(unquote(a) + unquote(b)) };\ndos(1)",
+			"2:4: Numero equivocado de argumentos para el macro dos. Son: 1, deberian ser 2",
+		},
+		{
+			"enchanted roto = folklore(a) { nada };\nroto(1)",
+			"2:5: Error en el macro roto: identifier not found: nada",
+		},
+		{
+			"enchanted cinco = folklore() { 5 };\ncinco()",
+			"2:6: El macro cinco deberia devolver un quote, no INTEGER",
+		},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		_, errors := ExpandMacros(program, env)
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("errores erroneos para %q. esperado=%q, obtenido=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package evaluator
+
+import (
+	"main/object"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Biblioteca matematica. Todas aceptan enteros y flotantes:
+//   - abs, min y max conservan el tipo: con puros enteros devuelven un
+//     entero, y si algun argumento es flotante devuelven un flotante.
+//   - floor, ceil y round devuelven un entero; un entero queda igual.
+//   - Las demas convierten los enteros a flotante y devuelven un flotante.
+//     Un resultado que no es un numero finito (sqrt(-1), log(0)) es un
+//     error, porque el lenguaje no tiene Inf ni NaN.
+
+// mathConstants son los nombres predefinidos que no son funciones.
+var mathConstants = map[string]object.Object{
+	"PI": &object.Float{Value: math.Pi},
+	"E":  &object.Float{Value: math.E},
+}
+
+func numberParam(name string) object.Param {
+	return object.Param{Name: name, Types: []object.ObjectType{object.INTEGER_OBJ, object.BIGINT_OBJ, object.FLOAT_OBJ}}
+}
+
+var integerParam = object.Param{Name: "n", Types: []object.ObjectType{object.INTEGER_OBJ}}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return f
+	}
+	return obj.(*object.Float).Value
+}
+
+// floatBuiltin arma un builtin que opera con sus argumentos como flotantes.
+func floatBuiltin(name string, params []string, fn func(args []float64) float64) *object.Builtin {
+	signature := object.Signature{}
+	types := make([]string, len(params))
+	for i, param := range params {
+		signature.Params = append(signature.Params, numberParam(param))
+		types[i] = "any"
+	}
+	signature.Type = "isme(" + strings.Join(types, ", ") + ") float"
+	return &object.Builtin{
+		Name:      name,
+		Signature: signature,
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			values := make([]float64, len(args))
+			for i, arg := range args {
+				values[i] = toFloat(arg)
+			}
+			result := fn(values)
+			if math.IsNaN(result) || math.IsInf(result, 0) {
+				return createError("Valor invalido para `%s`: el resultado no es un numero finito", name)
+			}
+			return &object.Float{Value: result}
+		},
+	}
+}
+
+// roundingBuiltin arma floor, ceil y round, que llevan un flotante al entero
+// que da fn.
+func roundingBuiltin(name string, fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Name:      name,
+		Signature: object.Signature{Params: []object.Param{numberParam("x")}, Type: "isme(any) int"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			f, ok := args[0].(*object.Float)
+			if !ok {
+				return args[0]
+			}
+			if math.IsInf(f.Value, 0) || math.IsNaN(f.Value) {
+				return createError("Valor invalido para `%s`: %s no es un numero finito", name, f.Inspect())
+			}
+			n, _ := big.NewFloat(fn(f.Value)).Int(nil)
+			return normalizeBigInt(n)
+		},
+	}
+}
+
+// extremeBuiltin arma min y max: se queda con el argumento para el que
+// better da SparksFly contra el mejor hasta el momento.
+func extremeBuiltin(name, better string) *object.Builtin {
+	return &object.Builtin{
+		Name: name,
+		Signature: object.Signature{
+			Params:   []object.Param{numberParam("x"), numberParam("valores")},
+			Variadic: true,
+			Type:     "isme(any, any) any",
+		},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			best, float := args[0], false
+			for _, arg := range args {
+				float = float || arg.Type() == object.FLOAT_OBJ
+				if evalInfixExpression(better, arg, best) == TRUE {
+					best = arg
+				}
+			}
+			if float {
+				return &object.Float{Value: toFloat(best)}
+			}
+			return best
+		},
+	}
+}
+
+var mathBuiltins = []*object.Builtin{
+	{
+		Name:      "abs",
+		Signature: object.Signature{Params: []object.Param{numberParam("x")}, Type: "isme(a) a"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			switch arg := args[0].(type) {
+			case *object.Float:
+				return &object.Float{Value: math.Abs(arg.Value)}
+			case *object.Integer:
+				if arg.Value >= 0 {
+					return arg
+				}
+			}
+			return evalMinusPrefixOperatorExpression(args[0])
+		},
+	},
+	extremeBuiltin("min", "<"),
+	extremeBuiltin("max", ">"),
+	roundingBuiltin("floor", math.Floor),
+	roundingBuiltin("ceil", math.Ceil),
+	roundingBuiltin("round", math.Round),
+	floatBuiltin("sqrt", []string{"x"}, func(args []float64) float64 { return math.Sqrt(args[0]) }),
+	floatBuiltin("pow", []string{"base", "exponente"}, func(args []float64) float64 { return math.Pow(args[0], args[1]) }),
+	floatBuiltin("sin", []string{"x"}, func(args []float64) float64 { return math.Sin(args[0]) }),
+	floatBuiltin("cos", []string{"x"}, func(args []float64) float64 { return math.Cos(args[0]) }),
+	floatBuiltin("tan", []string{"x"}, func(args []float64) float64 { return math.Tan(args[0]) }),
+	floatBuiltin("atan", []string{"x"}, func(args []float64) float64 { return math.Atan(args[0]) }),
+	floatBuiltin("log", []string{"x"}, func(args []float64) float64 { return math.Log(args[0]) }),
+	floatBuiltin("exp", []string{"x"}, func(args []float64) float64 { return math.Exp(args[0]) }),
+
+	{
+		Name:      "random",
+		Signature: object.Signature{Type: "isme() float"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return &object.Float{Value: rt.Rand().Float64()}
+		},
+	},
+	{
+		Name:      "randomInt",
+		Signature: object.Signature{Params: []object.Param{integerParam}, Type: "isme(int) int"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			n := args[0].(*object.Integer).Value
+			if n <= 0 {
+				return createError("Valor invalido para `randomInt`: n deberia ser positivo, es %d", n)
+			}
+			return &object.Integer{Value: rt.Rand().Int63n(n)}
+		},
+	},
+	{
+		Name:      "seed",
+		Signature: object.Signature{Params: []object.Param{integerParam}, Type: "isme(int) null"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			rt.Seed(args[0].(*object.Integer).Value)
+			return NULL
+		},
+	},
+}
@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"main/ast"
+	"main/lexer"
+	"main/object"
+	"main/parser"
+	"main/resolver"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func evalImportExpression(
+	node *ast.ImportExpression,
+	env *object.Environment,
+) object.Object {
+	path, err := resolveModulePath(node.Path, env.File())
+	if err != nil {
+		return createError("No se pudo resolver el modulo %q: %s", node.Path, err)
+	}
+
+	runtime := env.Runtime()
+	if module, ok := runtime.Modules[path]; ok {
+		return module
+	}
+	for i, importing := range runtime.Importing {
+		if importing == path {
+			cycle := append(append([]string{}, runtime.Importing[i:]...), path)
+			return createError("Importacion circular: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return createError("No se pudo leer el modulo %q: %s", node.Path, err)
+	}
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return createError("Errores de sintaxis en el modulo %q: %s",
+			node.Path, strings.Join(p.Errors(), "; "))
+	}
+
+	macroEnv := object.NewModuleEnvironment(env, path)
+	DefineMacros(program, macroEnv)
+	expanded, errors := ExpandMacros(program, macroEnv)
+	if len(errors) != 0 {
+		return createError("Errores en el modulo %q: %s",
+			node.Path, strings.Join(errors, "; "))
+	}
+
+	r := resolver.New()
+	r.Predeclare(BuiltinNames()...)
+	r.Resolve(expanded.(*ast.Program))
+	if len(r.Errors()) != 0 {
+		return createError("Errores en el modulo %q: %s",
+			node.Path, strings.Join(r.Errors(), "; "))
+	}
+
+	runtime.Importing = append(runtime.Importing, path)
+	moduleEnv := object.NewModuleEnvironment(env, path)
+	result := Eval(expanded, moduleEnv)
+	runtime.Importing = runtime.Importing[:len(runtime.Importing)-1]
+	if isError(result) {
+		return result
+	}
+
+	module := &object.Module{Path: path, Env: moduleEnv}
+	runtime.Modules[path] = module
+	return module
+}
+
+// resolveModulePath interpreta la ruta de un `feat` relativa al archivo que
+// lo contiene, o al directorio actual si el importador no viene de archivo.
+func resolveModulePath(path, importer string) (string, error) {
+	if !filepath.IsAbs(path) && importer != "" {
+		path = filepath.Join(filepath.Dir(importer), path)
+	}
+	return filepath.Abs(path)
+}
+
+func evalModuleIndexExpression(module, index object.Object) object.Object {
+	moduleObject := module.(*object.Module)
+	name, ok := index.(*object.String)
+	if !ok {
+		return createError("Los nombres de un modulo son strings, no: %s", index.Type())
+	}
+	val, ok := moduleObject.Env.Get(name.Value)
+	if !ok {
+		return createError("El modulo %s no define: %s", moduleObject.Path, name.Value)
+	}
+	return val
+}
@@ -0,0 +1,121 @@
+package evaluator
+
+import (
+	"main/lexer"
+	"main/object"
+	"main/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeModules(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func testEvalFile(t *testing.T, dir, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores del parser: %v", p.Errors())
+	}
+	env := object.NewEnvironment()
+	env.SetFile(filepath.Join(dir, "main.sp"))
+	return Eval(program, env)
+}
+
+func TestImportExpression(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"mate.sp": `enchanted doble = isme(x) { x * 2 };
+enchanted base = 10;`,
+		"lib/texto.sp": `enchanted util = feat "util.sp";
+enchanted saludo = "hola " + util["nombre"];`,
+		"lib/util.sp": `enchanted nombre = "taylor";`,
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`enchanted m = feat "mate.sp"; m["doble"](m["base"])`, 20},
+		{`feat "mate.sp"["base"]`, 10},
+		{`enchanted t = feat "lib/texto.sp"; t["saludo"]`, "hola taylor"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalFile(t, dir, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("obj no es un string. Sino: %T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("string erroneo. Obtuvo: %q, en vez de: %q", str.Value, expected)
+			}
+		}
+	}
+}
+
+func TestImportIsCached(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"contador.sp": `enchanted valores = [1, 2, 3];`,
+	})
+
+	input := `enchanted a = feat "contador.sp";
+enchanted b = feat "./contador.sp";
+a == b`
+	testBoolObject(t, testEvalFile(t, dir, input), true)
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"a.sp":      `enchanted b = feat "b.sp";`,
+		"b.sp":      `enchanted a = feat "a.sp";`,
+		"malo.sp":   `enchanted = 5;`,
+		"falla.sp":  `5 + SparksFly;`,
+		"ok.sp":     `enchanted x = 1;`,
+		"nombre.sp": `enchanted x = y;`,
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`feat "a.sp"`, "Importacion circular: " + filepath.Join(dir, "a.sp") + " -> " +
+			filepath.Join(dir, "b.sp") + " -> " + filepath.Join(dir, "a.sp")},
+		{`feat "noexiste.sp"`, `No se pudo leer el modulo "noexiste.sp"`},
+		{`feat "malo.sp"`, `Errores de sintaxis en el modulo "malo.sp"`},
+		{`feat "falla.sp"`, "Error de tipos: INTEGER + BOOL"},
+		{`feat "nombre.sp"`, `Errores en el modulo "nombre.sp": 1:15: identifier not found: y`},
+		{`feat "ok.sp"["y"]`, "El modulo " + filepath.Join(dir, "ok.sp") + " no define: y"},
+		{`feat "ok.sp"[1]`, "Los nombres de un modulo son strings, no: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalFile(t, dir, tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%s: no se retorno un error. Sino: %T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if !strings.HasPrefix(errObj.Message, tt.expected) {
+			t.Errorf("%s: mensaje erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
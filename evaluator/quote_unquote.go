@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"fmt"
+	"main/ast"
+	"main/object"
+	"main/token"
+	"math/big"
+)
+
+// quote trabaja sobre una copia: cada llamada, por ejemplo cada expansion
+// de un macro, necesita sus propios nodos.
+func quote(node ast.Node, env *object.Environment) object.Object {
+	copied, err := ast.Copy(node)
+	if err != nil {
+		return createError("%s", err)
+	}
+	copied, errObj := evalUnquoteCalls(copied, env)
+	if errObj != nil {
+		return errObj
+	}
+	return &object.Quote{Node: copied}
+}
+
+// evalUnquoteCalls reemplaza cada `unquote(x)` dentro de un quote por el
+// nodo que representa el valor de x. El primer unquote que falla, o cuyo
+// valor no se puede escribir como codigo, deja el arbol como estaba y su
+// error es el resultado.
+func evalUnquoteCalls(quoted ast.Node, env *object.Environment) (ast.Node, *object.Error) {
+	var err *object.Error
+	quoted = ast.Modify(quoted, func(node ast.Node) ast.Node {
+		if err != nil || !isUnquoteCall(node) {
+			return node
+		}
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+		if len(call.Arguments) != 1 {
+			return node
+		}
+		unquoted := Eval(call.Arguments[0], env)
+		converted, convErr := convertObjectToASTNode(unquoted, call.Token)
+		if convErr != nil {
+			err = convErr
+			return node
+		}
+		return converted
+	})
+	return quoted, err
+}
+
+func isUnquoteCall(node ast.Node) bool {
+	callExpression, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+	return callExpression.Function.TokenLiteral() == "unquote"
+}
+
+// convertObjectToASTNode convierte el resultado de un unquote de vuelta en
+// un nodo; tok es el token de la llamada, usado para las posiciones. Los
+// valores que no tienen literal, como las funciones, son un error.
+func convertObjectToASTNode(obj object.Object, tok token.Token) (ast.Expression, *object.Error) {
+	at := func(tokenType token.TokenType, literal string) token.Token {
+		return token.Token{Type: tokenType, Literal: literal, Line: tok.Line, Column: tok.Column}
+	}
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return &ast.IntegerLiteral{Token: at(token.INT, fmt.Sprintf("%d", obj.Value)), Value: obj.Value}, nil
+	case *object.BigInt:
+		return &ast.IntegerLiteral{Token: at(token.INT, obj.Value.String()), Big: new(big.Int).Set(obj.Value)}, nil
+	case *object.Float:
+		return &ast.FloatLiteral{Token: at(token.FLOAT, fmt.Sprintf("%g", obj.Value)), Value: obj.Value}, nil
+	case *object.String:
+		return &ast.StringLiteral{Token: at(token.STRING, obj.Value), Value: obj.Value}, nil
+	case *object.Bool:
+		if obj.Value {
+			return &ast.Boolean{Token: at(token.TRUE, "SparksFly"), Value: true}, nil
+		}
+		return &ast.Boolean{Token: at(token.FALSE, "BadBlood"), Value: false}, nil
+	case *object.Null:
+		return &ast.NullLiteral{Token: at(token.NULL, "BlankSpace")}, nil
+	case *object.Array:
+		elements := make([]ast.Expression, len(obj.Elements))
+		for i, element := range obj.Elements {
+			converted, err := convertObjectToASTNode(element, tok)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = converted
+		}
+		return &ast.ArrayLiteral{Token: at(token.LBRACKET, "["), Elements: elements}, nil
+	case *object.Hash:
+		pairs := make([]ast.HashPair, 0, obj.Len())
+		for _, pair := range obj.Ordered() {
+			key, err := convertObjectToASTNode(pair.Key, tok)
+			if err != nil {
+				return nil, err
+			}
+			value, err := convertObjectToASTNode(pair.Value, tok)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, ast.HashPair{Key: key, Value: value})
+		}
+		return &ast.HashLiteral{Token: at(token.LBRACE, "{"), Pairs: pairs}, nil
+	case *object.Quote:
+		// Se copia como en quote: el resolver anota cada Variable en su
+		// lugar, y dos unquote del mismo argumento pueden estar en scopes
+		// distintos.
+		if expression, ok := obj.Node.(ast.Expression); ok {
+			copied, err := ast.Copy(expression)
+			if err != nil {
+				return nil, createError("%s", err)
+			}
+			return copied.(ast.Expression), nil
+		}
+		return nil, createError("`unquote` no puede insertar la sentencia %s en una expresion", obj.Node.String())
+	case *object.Error:
+		return nil, obj
+	default:
+		return nil, createError("`unquote` no puede convertir %s en codigo", obj.Type())
+	}
+}
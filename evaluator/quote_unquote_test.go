@@ -0,0 +1,92 @@
+package evaluator
+
+import (
+	"main/object"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `5`},
+		{`quote(5 + 8)`, `(5 + 8)`},
+		{`quote(foobar)`, `foobar`},
+		{`quote(foobar + barfoo)`, `(foobar + barfoo)`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testQuoteObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(unquote(4))`, `4`},
+		{`quote(unquote(4 + 4))`, `8`},
+		{`quote(8 + unquote(4 + 4))`, `(8 + 8)`},
+		{`quote(unquote(4 + 4) + 8)`, `(8 + 8)`},
+		{`quote(unquote(1.5 * 2))`, `3`},
+		{`quote(unquote("taylor"))`, `taylor`},
+		{`enchanted foobar = 8; quote(foobar)`, `foobar`},
+		{`enchanted foobar = 8; quote(unquote(foobar))`, `8`},
+		{`quote(unquote(SparksFly))`, `SparksFly`},
+		{`quote(unquote(SparksFly == BadBlood))`, `BadBlood`},
+		{`quote(unquote(quote(4 + 4)))`, `(4 + 4)`},
+		{`enchanted quotedInfixExpression = quote(4 + 4);
+		quote(unquote(4 + 4) + unquote(quotedInfixExpression))`, `(8 + (4 + 4))`},
+		{`quote(unquote(BlankSpace))`, `BlankSpace`},
+		{`quote(unquote([1, 2]))`, `[1, 2]`},
+		{`quote(unquote([[1], "a", BlankSpace]))`, `[[1], a, BlankSpace]`},
+		{`quote(unquote({"a": [1], 2: SparksFly}))`, `{a:[1], 2:SparksFly}`},
+		{`quote(len(unquote([1, 2])))`, `len([1, 2])`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testQuoteObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestUnquoteErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(unquote(isme(x) { x }))`, "`unquote` no puede convertir FUNCTION en codigo"},
+		{`quote(unquote([1, len]))`, "`unquote` no puede convertir BUILTIN en codigo"},
+		{`quote(unquote(nada) + 1)`, "identifier not found: nada"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%s: se esperaba *object.Error. Obtuvo: %T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("%s: mensaje equivocado. Obtuvo: %q, en vez de: %q", tt.input, errObj.Message, tt.expected)
+		}
+	}
+}
+
+func testQuoteObject(t *testing.T, evaluated object.Object, expected string) {
+	quote, ok := evaluated.(*object.Quote)
+	if !ok {
+		t.Fatalf("se esperaba *object.Quote. Obtuvo: %T (%+v)", evaluated, evaluated)
+	}
+
+	if quote.Node == nil {
+		t.Fatalf("quote.Node es nil")
+	}
+
+	if quote.Node.String() != expected {
+		t.Errorf("no son iguales. Obtuvo: %q, en vez de: %q", quote.Node.String(), expected)
+	}
+}
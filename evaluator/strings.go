@@ -0,0 +1,243 @@
+package evaluator
+
+import (
+	"fmt"
+	"main/object"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Biblioteca de strings. Las posiciones (indexOf, chars) cuentan caracteres,
+// no bytes. parseInt y parseFloat devuelven BlankSpace si el texto no es un
+// numero, para poder usarlos con `??` sobre lo que llega de input.
+
+// maxStringLength es el largo maximo, en bytes, de un string que arma un
+// builtin como repeat. Lo que queda por debajo igual cuenta en
+// MaxAllocations.
+const maxStringLength = 1 << 28
+
+func stringParam(name string) object.Param {
+	return object.Param{Name: name, Types: []object.ObjectType{object.STRING_OBJ}}
+}
+
+func stringBuiltin(name string, params []object.Param, typ string, fn func(args []string) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Name:      name,
+		Signature: object.Signature{Params: params, Type: typ},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			values := make([]string, len(args))
+			for i, arg := range args {
+				values[i] = arg.(*object.String).Value
+			}
+			return fn(values)
+		},
+	}
+}
+
+var stringBuiltins = []*object.Builtin{
+	stringBuiltin("split", []object.Param{stringParam("s"), stringParam("sep")}, "isme(string, string) [string]", func(args []string) object.Object {
+		parts := strings.Split(args[0], args[1])
+		elements := make([]object.Object, len(parts))
+		for i, part := range parts {
+			elements[i] = &object.String{Value: part}
+		}
+		return &object.Array{Elements: elements}
+	}),
+	{
+		Name:      "join",
+		Signature: object.Signature{Params: []object.Param{arrayParam, stringParam("sep")}, Type: "isme([string], string) string"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			parts := make([]string, len(elements))
+			for i, element := range elements {
+				str, ok := element.(*object.String)
+				if !ok {
+					return createError("Tipo sin soporte para `join`: el elemento %d deberia ser STRING, no %s",
+						i, element.Type())
+				}
+				parts[i] = str.Value
+			}
+			return &object.String{Value: strings.Join(parts, args[1].(*object.String).Value)}
+		},
+	},
+	stringBuiltin("trim", []object.Param{stringParam("s")}, "isme(string) string", func(args []string) object.Object {
+		return &object.String{Value: strings.TrimSpace(args[0])}
+	}),
+	stringBuiltin("upper", []object.Param{stringParam("s")}, "isme(string) string", func(args []string) object.Object {
+		return &object.String{Value: strings.ToUpper(args[0])}
+	}),
+	stringBuiltin("lower", []object.Param{stringParam("s")}, "isme(string) string", func(args []string) object.Object {
+		return &object.String{Value: strings.ToLower(args[0])}
+	}),
+	stringBuiltin("contains", []object.Param{stringParam("s"), stringParam("sub")}, "isme(string, string) bool", func(args []string) object.Object {
+		return nativeBoolToBooleanObject(strings.Contains(args[0], args[1]))
+	}),
+	stringBuiltin("startsWith", []object.Param{stringParam("s"), stringParam("prefijo")}, "isme(string, string) bool", func(args []string) object.Object {
+		return nativeBoolToBooleanObject(strings.HasPrefix(args[0], args[1]))
+	}),
+	stringBuiltin("endsWith", []object.Param{stringParam("s"), stringParam("sufijo")}, "isme(string, string) bool", func(args []string) object.Object {
+		return nativeBoolToBooleanObject(strings.HasSuffix(args[0], args[1]))
+	}),
+	stringBuiltin("replace", []object.Param{stringParam("s"), stringParam("viejo"), stringParam("nuevo")}, "isme(string, string, string) string",
+		func(args []string) object.Object {
+			return &object.String{Value: strings.ReplaceAll(args[0], args[1], args[2])}
+		}),
+	stringBuiltin("indexOf", []object.Param{stringParam("s"), stringParam("sub")}, "isme(string, string) int", func(args []string) object.Object {
+		i := strings.Index(args[0], args[1])
+		if i < 0 {
+			return &object.Integer{Value: -1}
+		}
+		return &object.Integer{Value: int64(utf8.RuneCountInString(args[0][:i]))}
+	}),
+	{
+		Name: "repeat",
+		Signature: object.Signature{Params: []object.Param{
+			stringParam("s"),
+			{Name: "n", Types: []object.ObjectType{object.INTEGER_OBJ}},
+		}, Type: "isme(string, int) string"},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			s, n := args[0].(*object.String).Value, args[1].(*object.Integer).Value
+			if n < 0 {
+				return createError("Valor invalido para `repeat`: n no puede ser negativo, es %d", n)
+			}
+			if s == "" || n == 0 {
+				return &object.String{Value: ""}
+			}
+			// Se compara dividiendo para que len(s) * n no se desborde.
+			if n > maxStringLength/int64(len(s)) {
+				return createError("Valor invalido para `repeat`: el resultado tendria mas de %d bytes", maxStringLength)
+			}
+			return &object.String{Value: strings.Repeat(s, int(n))}
+		},
+	},
+	stringBuiltin("chars", []object.Param{stringParam("s")}, "isme(string) [string]", func(args []string) object.Object {
+		elements := make([]object.Object, 0, utf8.RuneCountInString(args[0]))
+		for _, r := range args[0] {
+			elements = append(elements, &object.String{Value: string(r)})
+		}
+		return &object.Array{Elements: elements}
+	}),
+	stringBuiltin("parseInt", []object.Param{stringParam("s")}, "isme(string) int", func(args []string) object.Object {
+		n, ok := new(big.Int).SetString(strings.TrimSpace(args[0]), 10)
+		if !ok {
+			return NULL
+		}
+		return normalizeBigInt(n)
+	}),
+	stringBuiltin("parseFloat", []object.Param{stringParam("s")}, "isme(string) float", func(args []string) object.Object {
+		f, err := strconv.ParseFloat(strings.TrimSpace(args[0]), 64)
+		if err != nil {
+			return NULL
+		}
+		return &object.Float{Value: f}
+	}),
+	{
+		Name: "format",
+		Signature: object.Signature{
+			Params:   []object.Param{stringParam("formato"), {Name: "valores"}},
+			Variadic: true,
+			Type:     "isme(string, any) string",
+		},
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			return formatString(args[0].(*object.String).Value, args[1:])
+		},
+	},
+}
+
+// formatString es el printf de `format`. Entiende %s y %v con cualquier
+// valor, %d con enteros y %f con numeros, con las banderas, ancho y
+// precision de fmt, y %% para un %. A diferencia de fmt, un verbo que no
+// corresponde con su argumento, o que sobren o falten argumentos, es un error.
+func formatString(format string, args []object.Object) object.Object {
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+
+		// Un formato es %, banderas, un ancho y una precision opcionales y
+		// el verbo: %-8.2f.
+		j := i + 1
+		for j < len(format) && strings.IndexByte("-+ 0#", format[j]) >= 0 {
+			j++
+		}
+		j = skipDigits(format, j)
+		if j < len(format) && format[j] == '.' {
+			j = skipDigits(format, j+1)
+		}
+		if j < len(format) && (format[j] == '.' || isDigit(format[j])) {
+			end := j
+			for end < len(format) && (format[end] == '.' || isDigit(format[end])) {
+				end++
+			}
+			end = min(end+1, len(format))
+			return createError("Formato invalido para `format`: %s tiene el ancho o la precision mal escritos", format[i:end])
+		}
+		if j == len(format) {
+			return createError("Formato invalido para `format`: %s no tiene verbo", format[i:])
+		}
+		spec := format[i : j+1]
+		i = j
+
+		if spec == "%%" {
+			out.WriteByte('%')
+			continue
+		}
+		if next == len(args) {
+			return createError("Formato invalido para `format`: falta el valor para %s", spec)
+		}
+		value, err := formatValue(spec, args[next])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, spec, value)
+		next++
+	}
+	if next < len(args) {
+		return createError("Formato invalido para `format`: sobran %d valores", len(args)-next)
+	}
+	return &object.String{Value: out.String()}
+}
+
+func skipDigits(s string, i int) int {
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// formatValue convierte arg al valor de Go que fmt espera para spec.
+func formatValue(spec string, arg object.Object) (interface{}, *object.Error) {
+	switch verb := spec[len(spec)-1]; verb {
+	case 's', 'v':
+		return arg.Inspect(), nil
+	case 'd':
+		switch arg := arg.(type) {
+		case *object.Integer:
+			return arg.Value, nil
+		case *object.BigInt:
+			return arg.Value, nil
+		}
+	case 'f':
+		switch arg := arg.(type) {
+		case *object.Float:
+			return arg.Value, nil
+		case *object.Integer:
+			return float64(arg.Value), nil
+		case *object.BigInt:
+			// big.Float guarda el entero completo, sin perder digitos.
+			return new(big.Float).SetInt(arg.Value), nil
+		}
+	default:
+		return nil, createError("Formato invalido para `format`: verbo desconocido %s", spec)
+	}
+	return nil, createError("Tipo sin soporte para `format`: %s no acepta %s", spec, arg.Type())
+}
@@ -0,0 +1,219 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"main/ast"
+	"main/evaluator"
+	"main/lexer"
+	"main/object"
+	"main/optimizer"
+	"main/parser"
+	"main/resolver"
+	"main/typechecker"
+	"strings"
+)
+
+// Stage es la etapa en la que fallo el codigo.
+type Stage string
+
+const (
+	ParseStage   Stage = "sintaxis"
+	MacroStage   Stage = "macros"
+	ResolveStage Stage = "nombres"
+	TypeStage    Stage = "tipos"
+	RuntimeStage Stage = "ejecucion"
+)
+
+// Error es lo que devuelven Run y Call cuando el codigo no corre: los
+// errores de una etapa antes de ejecutar, o el error en ejecucion. Kind dice
+// si la ejecucion se corto por un limite o por el contexto, y Stack son las
+// llamadas en curso cuando ocurrio.
+type Error struct {
+	Stage    Stage
+	Messages []string
+	Kind     object.ErrorKind
+	Stack    []object.Frame
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("errores de %s: %s", e.Stage, strings.Join(e.Messages, "; "))
+}
+
+// Interpreter corre codigo del lenguaje desde un programa en Go. Pasa por
+// las mismas etapas que repl.Start, pero sin escribir archivos ni terminar
+// el proceso. Los nombres globales se conservan entre Run.
+//
+// Lo que escribe el programa se descarta y input no lee nada hasta que se
+// configuren con SetOutput y SetInput.
+type Interpreter struct {
+	env      *object.Environment
+	macroEnv *object.Environment
+	resolver *resolver.Resolver
+	checker  *typechecker.Checker
+}
+
+func New() *Interpreter {
+	r := resolver.New()
+	r.Predeclare(evaluator.BuiltinNames()...)
+	c := typechecker.New()
+	c.Predeclare(evaluator.Predeclared())
+	in := &Interpreter{
+		env:      object.NewEnvironment(),
+		macroEnv: object.NewEnvironment(),
+		resolver: r,
+		checker:  c,
+	}
+	in.SetOutput(io.Discard, io.Discard)
+	in.SetInput(strings.NewReader(""))
+	return in
+}
+
+// SetOutput cambia a donde escriben SpeakNow y los demas builtins.
+func (in *Interpreter) SetOutput(stdout, stderr io.Writer) {
+	rt := in.env.Runtime()
+	rt.Stdout, rt.Stderr = stdout, stderr
+}
+
+// SetInput cambia de donde lee input.
+func (in *Interpreter) SetInput(stdin io.Reader) {
+	in.env.Runtime().SetStdin(stdin)
+}
+
+// SetLimits fija los presupuestos de cada Run y Call.
+func (in *Interpreter) SetLimits(limits object.Limits) {
+	in.env.Runtime().Limits = limits
+}
+
+// Run corre source y devuelve el valor de su ultima sentencia.
+func (in *Interpreter) Run(source string) (object.Object, error) {
+	return in.RunContext(context.Background(), source)
+}
+
+// RunContext es Run, pero la ejecucion se corta si ctx se cancela.
+//
+// Si source falla en cualquier etapa, los nombres que declaraba se olvidan
+// para el resolver y el checker, y los siguientes Run pueden declararlos de
+// nuevo.
+func (in *Interpreter) RunContext(ctx context.Context, source string) (object.Object, error) {
+	resolved, checked := in.resolver.Snapshot(), in.checker.Snapshot()
+	result, err := in.run(ctx, source)
+	if err != nil {
+		in.resolver.Restore(resolved)
+		in.checker.Restore(checked)
+	}
+	return result, err
+}
+
+func (in *Interpreter) run(ctx context.Context, source string) (object.Object, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, &Error{Stage: ParseStage, Messages: p.Errors()}
+	}
+
+	evaluator.DefineMacros(program, in.macroEnv)
+	expanded, macroErrors := evaluator.ExpandMacros(program, in.macroEnv)
+	if len(macroErrors) != 0 {
+		return nil, &Error{Stage: MacroStage, Messages: macroErrors}
+	}
+	program = expanded.(*ast.Program)
+
+	in.resolver.Resolve(program)
+	if len(in.resolver.Errors()) != 0 {
+		return nil, &Error{Stage: ResolveStage, Messages: in.resolver.Errors()}
+	}
+
+	in.checker.Check(program)
+	if len(in.checker.Errors()) != 0 {
+		return nil, &Error{Stage: TypeStage, Messages: in.checker.Errors()}
+	}
+
+	program = optimizer.Optimize(program).(*ast.Program)
+	return in.eval(ctx, func() object.Object { return evaluator.Eval(program, in.env) })
+}
+
+// SetGlobal liga name con value para los siguientes Run.
+func (in *Interpreter) SetGlobal(name string, value object.Object) {
+	in.define(name, value, typeOf(value))
+}
+
+// define liga name en el entorno y lo anuncia al resolver y al checker con
+// el tipo t.
+func (in *Interpreter) define(name string, value object.Object, t typechecker.Type) {
+	in.env.Set(name, value)
+	in.resolver.Predeclare(name)
+	in.checker.Declare(name, t)
+}
+
+// GetGlobal devuelve el valor global de name.
+func (in *Interpreter) GetGlobal(name string) (object.Object, bool) {
+	return in.env.Get(name)
+}
+
+// Call llama a la funcion global name con args, igual que una llamada
+// desde el codigo.
+func (in *Interpreter) Call(name string, args ...object.Object) (object.Object, error) {
+	return in.CallContext(context.Background(), name, args...)
+}
+
+// CallContext es Call, pero la ejecucion se corta si ctx se cancela.
+func (in *Interpreter) CallContext(ctx context.Context, name string, args ...object.Object) (object.Object, error) {
+	fn, ok := in.env.Get(name)
+	if !ok {
+		return nil, &Error{Stage: RuntimeStage, Messages: []string{"identifier not found: " + name}}
+	}
+	switch fn.(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return nil, &Error{Stage: RuntimeStage,
+			Messages: []string{fmt.Sprintf("No es una funcion, sino: %s", fn.Type())}}
+	}
+	return in.eval(ctx, func() object.Object { return evaluator.ApplyFunction(fn, args, in.env.Runtime()) })
+}
+
+// eval corre run con los presupuestos en cero y convierte los *object.Error
+// y los panic del evaluador en un *Error, para que un programa roto no tire
+// abajo al programa que lo usa.
+func (in *Interpreter) eval(ctx context.Context, run func() object.Object) (result object.Object, err error) {
+	in.env.Runtime().Begin(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = &Error{Stage: RuntimeStage, Messages: []string{fmt.Sprintf("panic: %v", r)}}
+		}
+	}()
+
+	result = run()
+	if errObj, ok := result.(*object.Error); ok {
+		message := errObj.Message
+		if errObj.Position != "" {
+			message = errObj.Position + ": " + message
+		}
+		return nil, &Error{Stage: RuntimeStage, Messages: []string{message},
+			Kind: errObj.Kind, Stack: errObj.Stack}
+	}
+	return result, nil
+}
+
+// typeOf es el tipo con el que el checker ve un valor puesto desde Go.
+func typeOf(value object.Object) typechecker.Type {
+	switch value.(type) {
+	case *object.Integer, *object.BigInt:
+		return typechecker.Int
+	case *object.Float:
+		return typechecker.Float
+	case *object.Bool:
+		return typechecker.Bool
+	case *object.String:
+		return typechecker.String
+	case *object.Null:
+		return typechecker.Null
+	case *object.Array:
+		return &typechecker.Array{Elem: typechecker.Any}
+	case *object.Hash:
+		return &typechecker.Hash{Key: typechecker.Any, Value: typechecker.Any}
+	}
+	return typechecker.Any
+}
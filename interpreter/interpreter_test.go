@@ -0,0 +1,231 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"main/object"
+	"strings"
+	"testing"
+	"time"
+)
+
+func run(t *testing.T, in *Interpreter, source string) object.Object {
+	result, err := in.Run(source)
+	if err != nil {
+		t.Fatalf("%q: error inesperado: %v", source, err)
+	}
+	return result
+}
+
+func testInteger(t *testing.T, obj object.Object, expected int64) {
+	integer, ok := obj.(*object.Integer)
+	if !ok {
+		t.Fatalf("se esperaba un Integer. obtuvo=%T (%+v)", obj, obj)
+	}
+	if integer.Value != expected {
+		t.Errorf("valor erroneo. esperado=%d, obtenido=%d", expected, integer.Value)
+	}
+}
+
+func TestRun(t *testing.T) {
+	in := New()
+	testInteger(t, run(t, in, "enchanted x = 2 * 21; x"), 42)
+
+	// Los nombres siguen definidos en el siguiente Run.
+	run(t, in, "enchanted doble = isme(n) { n * 2 };")
+	testInteger(t, run(t, in, "doble(x)"), 84)
+
+	run(t, in, "enchanted porDos = folklore(a) { quote(unquote(a) * 2) };")
+	testInteger(t, run(t, in, "porDos(5)"), 10)
+}
+
+func TestUnquoteTwiceInDifferentScopes(t *testing.T) {
+	in := New()
+	run(t, in, "enchanted dos = folklore(e) { quote([isme(z) { unquote(e) }(0), unquote(e)]) };")
+	if got := run(t, in, "enchanted y = 7; dos(y)").Inspect(); got != "[7, 7]" {
+		t.Errorf("resultado erroneo. esperado=[7, 7], obtenido=%s", got)
+	}
+}
+
+func TestRedeclareAcrossRuns(t *testing.T) {
+	in := New()
+	testInteger(t, run(t, in, "enchanted x = 1; x"), 1)
+	testInteger(t, run(t, in, "enchanted x = 2; x"), 2)
+
+	str, ok := run(t, in, `enchanted x = "otro tipo"; x`).(*object.String)
+	if !ok || str.Value != "otro tipo" {
+		t.Errorf("x deberia ser el string nuevo. obtuvo=%v", str)
+	}
+
+	// En un mismo Run sigue siendo un duplicado.
+	if _, err := in.Run("enchanted y = 1; enchanted y = 2;"); err == nil {
+		t.Errorf("se esperaba un error por la variable duplicada")
+	}
+}
+
+func TestFailedRunIsForgotten(t *testing.T) {
+	tests := []struct {
+		failing string
+		stage   Stage
+	}{
+		{"enchanted y = 5 + SparksFly;", TypeStage},
+		{"enchanted y = 5; nada", ResolveStage},
+		{"enchanted y = 5; debut([]) + 1", RuntimeStage},
+	}
+
+	for _, tt := range tests {
+		in := New()
+		_, err := in.Run(tt.failing)
+		if e, ok := err.(*Error); !ok || e.Stage != tt.stage {
+			t.Fatalf("%q: se esperaba un error de %s. obtuvo=%v", tt.failing, tt.stage, err)
+		}
+		if _, err := in.Run("y"); err == nil || err.(*Error).Stage != ResolveStage {
+			t.Errorf("%q: y no deberia seguir declarada. obtuvo=%v", tt.failing, err)
+		}
+		testInteger(t, run(t, in, "enchanted y = 1; y"), 1)
+		testInteger(t, run(t, in, "enchanted z = 2; y + z"), 3)
+	}
+
+	// El checker tambien vuelve al tipo que tenia el nombre antes.
+	in := New()
+	in.SetGlobal("g", &object.Integer{Value: 1})
+	if _, err := in.Run(`enchanted g = "a"; 1 + SparksFly`); err == nil {
+		t.Fatalf("se esperaba un error de tipos")
+	}
+	testInteger(t, run(t, in, "g + 1"), 2)
+}
+
+func TestGlobals(t *testing.T) {
+	in := New()
+	in.SetGlobal("limite", &object.Integer{Value: 10})
+	testInteger(t, run(t, in, "limite + 1"), 11)
+
+	run(t, in, "enchanted total = limite * 3;")
+	total, ok := in.GetGlobal("total")
+	if !ok {
+		t.Fatalf("total deberia estar definido")
+	}
+	testInteger(t, total, 30)
+
+	// SetGlobal reemplaza tambien lo que definio el programa.
+	in.SetGlobal("total", &object.Integer{Value: 7})
+	testInteger(t, run(t, in, "total"), 7)
+
+	if _, ok := in.GetGlobal("nada"); ok {
+		t.Errorf("nada no deberia estar definido")
+	}
+}
+
+func TestCall(t *testing.T) {
+	in := New()
+	run(t, in, "enchanted suma = isme(a, b) { a + b }; enchanted x = 1;")
+
+	result, err := in.Call("suma", &object.Integer{Value: 2}, &object.Integer{Value: 3})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	testInteger(t, result, 5)
+
+	tests := []struct {
+		name     string
+		args     []object.Object
+		expected string
+	}{
+		{"suma", []object.Object{&object.Integer{Value: 1}},
+			"errores de ejecucion: Numero equivocado de argumentos. Son: 1, deberian ser 2"},
+		{"nada", nil, "errores de ejecucion: identifier not found: nada"},
+		{"x", nil, "errores de ejecucion: No es una funcion, sino: INTEGER"},
+	}
+	for _, tt := range tests {
+		_, err := in.Call(tt.name, tt.args...)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("Call(%q): error erroneo. esperado=%q, obtenido=%v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		source   string
+		stage    Stage
+		expected string
+	}{
+		{"enchanted = 1;", ParseStage, "Token esperado: ID, se obtuvo: ="},
+		{"enchanted m = folklore(a) { quote(unquote(a)) }; m(1, 2)", MacroStage,
+			"1:51: Numero equivocado de argumentos para el macro m. Son: 2, deberian ser 1"},
+		{"y + 1", ResolveStage, "1:1: identifier not found: y"},
+		{"1 + SparksFly", TypeStage, "1:3: Error de tipos: int + bool"},
+		{"debut([]) + 1", RuntimeStage, "1:11: Error de tipos: NULL + INTEGER"},
+		{"enchanted cero = 0; 1 / cero", RuntimeStage, "1:23: Division por cero"},
+	}
+
+	for _, tt := range tests {
+		result, err := New().Run(tt.source)
+		if result != nil {
+			t.Errorf("%q: no se esperaba un resultado. obtuvo=%v", tt.source, result.Inspect())
+		}
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%q: se esperaba un *Error. obtuvo=%T (%v)", tt.source, err, err)
+			continue
+		}
+		if e.Stage != tt.stage || len(e.Messages) == 0 || e.Messages[0] != tt.expected {
+			t.Errorf("%q: error erroneo. esperado=%s %q, obtenido=%s %q",
+				tt.source, tt.stage, tt.expected, e.Stage, e.Messages)
+		}
+	}
+}
+
+func TestOutputAndInput(t *testing.T) {
+	in := New()
+	// Sin configurar, la salida se descarta y no hay entrada.
+	if result := run(t, in, `SpeakNow("nadie lo ve"); input()`); result.Inspect() != "null" {
+		t.Errorf("input deberia dar BlankSpace sin entrada. obtuvo=%s", result.Inspect())
+	}
+
+	var out bytes.Buffer
+	in.SetOutput(&out, &out)
+	in.SetInput(strings.NewReader("mundo\n"))
+	run(t, in, `SpeakNow("hola " + input())`)
+	if out.String() != "hola mundo\n" {
+		t.Errorf("salida erronea. obtuvo=%q", out.String())
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	in := New()
+	run(t, in, "enchanted fib = isme(n) { LoverEra (n < 2) { n } RepEra { fib(n - 1) + fib(n - 2) } };")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := in.RunContext(ctx, "fib(40)")
+	if e, ok := err.(*Error); !ok || e.Kind != object.CanceledError ||
+		e.Messages[0] != "Ejecucion cancelada: context deadline exceeded" {
+		t.Errorf("fib(40) deberia cortarse por tiempo. obtuvo=%v", err)
+	}
+
+	in.SetLimits(object.Limits{MaxSteps: 1000})
+	_, err = in.Call("fib", &object.Integer{Value: 30})
+	if e, ok := err.(*Error); !ok || e.Kind != object.StepLimitError {
+		t.Errorf("fib(30) deberia pasarse de pasos. obtuvo=%v", err)
+	}
+	// Cada Run empieza con el presupuesto completo.
+	testInteger(t, run(t, in, "fib(5)"), 5)
+	testInteger(t, run(t, in, "fib(5)"), 5)
+}
+
+func TestErrorStack(t *testing.T) {
+	in := New()
+	run(t, in, "enchanted revisar = isme(x) { debut([]) + x }; enchanted dividir = isme(a, b) { revisar(b); a / b };")
+
+	_, err := in.Call("dividir", &object.Integer{Value: 1}, &object.Integer{Value: 0})
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("se esperaba un *Error. obtuvo=%v", err)
+	}
+	// La llamada que vino de Go no tiene posicion.
+	expected := []object.Frame{{Function: "revisar", Position: "1:88"}, {Function: "dividir"}}
+	if len(e.Stack) != 2 || e.Stack[0] != expected[0] || e.Stack[1] != expected[1] {
+		t.Errorf("pila erronea. esperada=%v, obtenida=%v", expected, e.Stack)
+	}
+}
@@ -0,0 +1,343 @@
+package interpreter
+
+import (
+	"fmt"
+	"main/evaluator"
+	"main/object"
+	"main/typechecker"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	objectType = reflect.TypeOf((*object.Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	bigIntType = reflect.TypeOf((*big.Int)(nil))
+)
+
+// Register expone fn, una funcion de Go, como la funcion global name de este
+// interprete. Los argumentos y el resultado se convierten solos entre los
+// tipos del lenguaje y bool, int, int64, float64, string, slices y
+// map[string]T de esos tipos; un parametro object.Object recibe el valor tal
+// cual y uno interface{} su equivalente en Go. fn puede devolver ademas un
+// error, que se convierte en un error del programa.
+func (in *Interpreter) Register(name string, fn interface{}) error {
+	builtin, t, err := native(name, fn)
+	if err != nil {
+		return err
+	}
+	in.define(name, builtin, t)
+	return nil
+}
+
+// native arma el builtin que llama a fn y el tipo con el que lo ve el
+// checker. La firma del builtin hace que el evaluador valide los argumentos
+// igual que en los builtins del lenguaje.
+func native(name string, fn interface{}) (*object.Builtin, typechecker.Type, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, nil, fmt.Errorf("%s: se esperaba una funcion, se obtuvo %T", name, fn)
+	}
+	ft := v.Type()
+
+	sig := object.Signature{Variadic: ft.IsVariadic()}
+	fnType := &typechecker.Function{Variadic: ft.IsVariadic(), Return: typechecker.Null}
+	for i := 0; i < ft.NumIn(); i++ {
+		param := paramType(ft, i)
+		if !supported(param) {
+			return nil, nil, fmt.Errorf("%s: tipo de parametro sin soporte: %s", name, param)
+		}
+		sig.Params = append(sig.Params, object.Param{Name: strconv.Itoa(i + 1), Types: objectTypes(param)})
+		fnType.Params = append(fnType.Params, checkerType(param))
+	}
+
+	outs := ft.NumOut()
+	if outs > 0 && ft.Out(outs-1) == errorType {
+		outs--
+	}
+	switch {
+	case outs > 1:
+		return nil, nil, fmt.Errorf("%s: se esperaba un solo resultado y un error opcional", name)
+	case outs == 1 && !supported(ft.Out(0)):
+		return nil, nil, fmt.Errorf("%s: tipo de resultado sin soporte: %s", name, ft.Out(0))
+	case outs == 1:
+		fnType.Return = checkerType(ft.Out(0))
+	}
+
+	builtin := &object.Builtin{Name: name, Signature: sig}
+	builtin.Fn = func(rt *object.Runtime, args ...object.Object) object.Object {
+		values := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			value, err := toGo(arg, paramType(ft, i))
+			if err != nil {
+				return &object.Error{Message: fmt.Sprintf("%s: argumento %d: %s", name, i+1, err)}
+			}
+			values[i] = value
+		}
+
+		results := v.Call(values)
+		if len(results) > outs && !results[outs].IsNil() {
+			return &object.Error{Message: fmt.Sprintf("%s: %s", name, results[outs].Interface().(error))}
+		}
+		if outs == 0 {
+			return evaluator.NULL
+		}
+		result, err := toObject(results[0])
+		if err != nil {
+			return &object.Error{Message: fmt.Sprintf("%s: resultado: %s", name, err)}
+		}
+		return result
+	}
+	return builtin, fnType, nil
+}
+
+// paramType es el tipo de Go del argumento i; los que sobran van al
+// parametro variadico.
+func paramType(ft reflect.Type, i int) reflect.Type {
+	if ft.IsVariadic() && i >= ft.NumIn()-1 {
+		return ft.In(ft.NumIn() - 1).Elem()
+	}
+	return ft.In(i)
+}
+
+func supported(t reflect.Type) bool {
+	if t == objectType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.String:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Slice:
+		return supported(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && supported(t.Elem())
+	}
+	return false
+}
+
+// objectTypes son los tipos de objeto que acepta un parametro de tipo t.
+func objectTypes(t reflect.Type) []object.ObjectType {
+	switch t.Kind() {
+	case reflect.Bool:
+		return []object.ObjectType{object.BOOL_OBJ}
+	case reflect.Int, reflect.Int64:
+		return []object.ObjectType{object.INTEGER_OBJ}
+	case reflect.Float64:
+		return []object.ObjectType{object.FLOAT_OBJ}
+	case reflect.String:
+		return []object.ObjectType{object.STRING_OBJ}
+	case reflect.Slice:
+		return []object.ObjectType{object.ARRAY_OBJ}
+	case reflect.Map:
+		return []object.ObjectType{object.HASH_OBJ}
+	}
+	return nil
+}
+
+func checkerType(t reflect.Type) typechecker.Type {
+	switch t.Kind() {
+	case reflect.Bool:
+		return typechecker.Bool
+	case reflect.Int, reflect.Int64:
+		return typechecker.Int
+	case reflect.Float64:
+		return typechecker.Float
+	case reflect.String:
+		return typechecker.String
+	case reflect.Slice:
+		return &typechecker.Array{Elem: checkerType(t.Elem())}
+	case reflect.Map:
+		return &typechecker.Hash{Key: typechecker.String, Value: checkerType(t.Elem())}
+	}
+	return typechecker.Any
+}
+
+// ---------------------------Conversiones--------------------------------
+
+// ToObject convierte un valor de Go al objeto equivalente del lenguaje,
+// por ejemplo para pasarlo a SetGlobal o a Call. Un *big.Int se convierte en
+// Integer si entra en int64 y en BigInt si no.
+func ToObject(v interface{}) (object.Object, error) {
+	if v == nil {
+		return evaluator.NULL, nil
+	}
+	return toObject(reflect.ValueOf(v))
+}
+
+func toObject(v reflect.Value) (object.Object, error) {
+	if v.Type() == objectType || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return evaluator.NULL, nil
+		}
+		if obj, ok := v.Interface().(object.Object); ok {
+			return obj, nil
+		}
+		return toObject(v.Elem())
+	}
+	if v.Type() == bigIntType {
+		if v.IsNil() {
+			return evaluator.NULL, nil
+		}
+		n := v.Interface().(*big.Int)
+		if n.IsInt64() {
+			return &object.Integer{Value: n.Int64()}, nil
+		}
+		return &object.BigInt{Value: new(big.Int).Set(n)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return evaluator.TRUE, nil
+		}
+		return evaluator.FALSE, nil
+	case reflect.Int, reflect.Int64:
+		return &object.Integer{Value: v.Int()}, nil
+	case reflect.Float64:
+		return &object.Float{Value: v.Float()}, nil
+	case reflect.String:
+		return &object.String{Value: v.String()}, nil
+	case reflect.Slice:
+		elements := make([]object.Object, v.Len())
+		for i := range elements {
+			element, err := toObject(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return &object.Array{Elements: elements}, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("las llaves de %s no son string", v.Type())
+		}
+		// Ordenadas, porque el hashMap guarda el orden de insercion.
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		hash := object.NewHash()
+		for _, k := range keys {
+			value, err := toObject(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			key := &object.String{Value: k.String()}
+			hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+		}
+		return hash, nil
+	}
+	return nil, fmt.Errorf("tipo sin soporte: %s", v.Type())
+}
+
+// toGo convierte obj al tipo t de un parametro de Go.
+func toGo(obj object.Object, t reflect.Type) (reflect.Value, error) {
+	if t == objectType {
+		return reflect.ValueOf(&obj).Elem(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		native, err := toNative(obj)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(t).Elem()
+		if native != nil {
+			result.Set(reflect.ValueOf(native))
+		}
+		return result, nil
+	case reflect.Bool:
+		if b, ok := obj.(*object.Bool); ok {
+			return reflect.ValueOf(b.Value).Convert(t), nil
+		}
+	case reflect.Int, reflect.Int64:
+		if i, ok := obj.(*object.Integer); ok {
+			return reflect.ValueOf(i.Value).Convert(t), nil
+		}
+	case reflect.Float64:
+		if f, ok := obj.(*object.Float); ok {
+			return reflect.ValueOf(f.Value).Convert(t), nil
+		}
+	case reflect.String:
+		if s, ok := obj.(*object.String); ok {
+			return reflect.ValueOf(s.Value).Convert(t), nil
+		}
+	case reflect.Slice:
+		if arr, ok := obj.(*object.Array); ok {
+			result := reflect.MakeSlice(t, len(arr.Elements), len(arr.Elements))
+			for i, element := range arr.Elements {
+				value, err := toGo(element, t.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				result.Index(i).Set(value)
+			}
+			return result, nil
+		}
+	case reflect.Map:
+		if hash, ok := obj.(*object.Hash); ok {
+			result := reflect.MakeMapWithSize(t, hash.Len())
+			for _, pair := range hash.Ordered() {
+				key, ok := pair.Key.(*object.String)
+				if !ok {
+					return reflect.Value{}, fmt.Errorf("la llave %s no es STRING", pair.Key.Inspect())
+				}
+				value, err := toGo(pair.Value, t.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				result.SetMapIndex(reflect.ValueOf(key.Value).Convert(t.Key()), value)
+			}
+			return result, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("no se puede convertir %s a %s", obj.Type(), t)
+}
+
+// toNative convierte obj al valor de Go mas natural: int64, float64, string,
+// bool, nil, []interface{} o map[string]interface{}. Lo demas, como las
+// funciones, se pasa como el object.Object.
+func toNative(obj object.Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return obj.Value, nil
+	case *object.BigInt:
+		return new(big.Int).Set(obj.Value), nil
+	case *object.Float:
+		return obj.Value, nil
+	case *object.String:
+		return obj.Value, nil
+	case *object.Bool:
+		return obj.Value, nil
+	case *object.Null:
+		return nil, nil
+	case *object.Array:
+		result := make([]interface{}, len(obj.Elements))
+		for i, element := range obj.Elements {
+			value, err := toNative(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	case *object.Hash:
+		result := make(map[string]interface{}, obj.Len())
+		for _, pair := range obj.Ordered() {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return nil, fmt.Errorf("la llave %s no es STRING", pair.Key.Inspect())
+			}
+			value, err := toNative(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			result[key.Value] = value
+		}
+		return result, nil
+	}
+	return obj, nil
+}
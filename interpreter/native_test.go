@@ -0,0 +1,168 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"main/object"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	in := New()
+	register := func(name string, fn interface{}) {
+		if err := in.Register(name, fn); err != nil {
+			t.Fatalf("Register(%q) fallo: %v", name, err)
+		}
+	}
+	register("sumar", func(a, b int) int { return a + b })
+	register("mitad", func(x float64) float64 { return x / 2 })
+	register("gritar", func(s string, fuerte bool) string {
+		if fuerte {
+			return strings.ToUpper(s) + "!"
+		}
+		return s
+	})
+	register("total", func(xs []int64) int64 {
+		var sum int64
+		for _, x := range xs {
+			sum += x
+		}
+		return sum
+	})
+	register("palabras", func(s string) []string { return strings.Fields(s) })
+	register("edades", func() map[string]int { return map[string]int{"b": 2, "a": 1} })
+	register("llaves", func(h map[string]int) int { return len(h) })
+	register("unir", func(sep string, partes ...string) string { return strings.Join(partes, sep) })
+	register("tipo", func(v interface{}) string { return fmt.Sprintf("%T", v) })
+	register("crudo", func(obj object.Object) object.Object { return obj })
+	register("mismo", func(v interface{}) interface{} { return v })
+	register("numero", strconv.Atoi)
+	register("nada", func() {})
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"sumar(2, 3)", "5"},
+		{"mitad(5.0)", "2.500000"},
+		{`gritar("hola", SparksFly)`, "HOLA!"},
+		{"total([1, 2, 3])", "6"},
+		{`len(palabras("a b c"))`, "3"},
+		{"edades()", "{a: 1, b: 2}"},
+		{`edades()["b"]`, "2"},
+		{`llaves({"x": 1, "y": 2})`, "2"},
+		{`unir("-", "a", "b", "c")`, "a-b-c"},
+		{`unir(",")`, ""},
+		{"tipo(1)", "int64"},
+		{"tipo([1, 2.5])", "[]interface {}"},
+		{"tipo(BlankSpace)", "<nil>"},
+		{"tipo(sumar)", "*object.Builtin"},
+		{"crudo([1])", "[1]"},
+		{"mismo(99999999999999999999)", "99999999999999999999"},
+		{"mismo(99999999999999999999) - 99999999999999999998", "1"},
+		{`numero("42")`, "42"},
+		{"nada()", "null"},
+		{"sumar(1, 2) == 3", "true"},
+	}
+	for _, tt := range tests {
+		result := run(t, in, tt.source)
+		if result.Inspect() != tt.expected {
+			t.Errorf("%q: resultado erroneo. esperado=%q, obtenido=%q", tt.source, tt.expected, result.Inspect())
+		}
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	in := New()
+	in.Register("sumar", func(a, b int) int { return a + b })
+	in.Register("numero", strconv.Atoi)
+	in.Register("total", func(xs []int) int { return len(xs) })
+	in.Register("fallar", func() error { return errors.New("algo salio mal") })
+
+	tests := []struct {
+		source   string
+		stage    Stage
+		expected string
+	}{
+		{`sumar("a", 1)`, TypeStage, "1:7: Error de tipos: se esperaba int, se obtuvo string"},
+		{"sumar(1)", TypeStage, "1:6: Numero equivocado de argumentos. Son: 1, deberian ser 2"},
+		{`numero("x")`, RuntimeStage, `1:7: numero: strconv.Atoi: parsing "x": invalid syntax`},
+		{"fallar()", RuntimeStage, "1:7: fallar: algo salio mal"},
+		{`enchanted xs = [1, "a"]; total(xs)`, RuntimeStage, "1:31: total: argumento 1: no se puede convertir STRING a int"},
+	}
+	for _, tt := range tests {
+		_, err := in.Run(tt.source)
+		e, ok := err.(*Error)
+		if !ok || e.Stage != tt.stage || e.Messages[0] != tt.expected {
+			t.Errorf("%q: error erroneo. esperado=%s %q, obtenido=%v", tt.source, tt.stage, tt.expected, err)
+		}
+	}
+
+	// Llamado desde Go no pasa por el checker: lo valida la firma.
+	_, err := in.Call("sumar", &object.String{Value: "a"}, &object.Integer{Value: 1})
+	expected := "errores de ejecucion: Tipo sin soporte para `sumar`: el argumento 1 deberia ser INTEGER, no STRING"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Call: error erroneo. esperado=%q, obtenido=%v", expected, err)
+	}
+}
+
+func TestRegisterIsPerInterpreter(t *testing.T) {
+	in := New()
+	in.Register("sumar", func(a, b int) int { return a + b })
+
+	_, err := New().Run("sumar(1, 2)")
+	if e, ok := err.(*Error); !ok || e.Stage != ResolveStage {
+		t.Errorf("otro interprete no deberia ver sumar. obtuvo=%v", err)
+	}
+}
+
+func TestRegisterRejects(t *testing.T) {
+	tests := []struct {
+		fn       interface{}
+		expected string
+	}{
+		{42, "f: se esperaba una funcion, se obtuvo int"},
+		{func(c chan int) {}, "f: tipo de parametro sin soporte: chan int"},
+		{func(m map[int]string) {}, "f: tipo de parametro sin soporte: map[int]string"},
+		{func() (int, int) { return 0, 0 }, "f: se esperaba un solo resultado y un error opcional"},
+		{func() *int { return nil }, "f: tipo de resultado sin soporte: *int"},
+	}
+	for _, tt := range tests {
+		err := New().Register("f", tt.fn)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("error erroneo. esperado=%q, obtenido=%v", tt.expected, err)
+		}
+	}
+}
+
+func TestToObject(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{7, "7"},
+		{[]interface{}{1, "a", true, nil}, "[1, a, true, null]"},
+		{map[string][]int{"z": {1}, "a": {}}, "{a: [], z: [1]}"},
+		{big.NewInt(5), "5"},
+		{new(big.Int).Lsh(big.NewInt(1), 64), "18446744073709551616"},
+		{[]interface{}{big.NewInt(2)}, "[2]"},
+	}
+	for _, tt := range tests {
+		obj, err := ToObject(tt.value)
+		if err != nil {
+			t.Errorf("ToObject(%v) fallo: %v", tt.value, err)
+			continue
+		}
+		if obj.Inspect() != tt.expected {
+			t.Errorf("ToObject(%v): esperado=%q, obtenido=%q", tt.value, tt.expected, obj.Inspect())
+		}
+	}
+
+	if _, err := ToObject(struct{}{}); err == nil || err.Error() != "tipo sin soporte: struct {}" {
+		t.Errorf("se esperaba un error para struct{}. obtuvo=%v", err)
+	}
+}
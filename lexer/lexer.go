@@ -6,6 +6,7 @@ import (
 	"io"
 	"main/token"
 	"os"
+	"strings"
 )
 
 type Lexer struct {
@@ -13,12 +14,28 @@ type Lexer struct {
 	position     int  // Index char actual
 	readPosition int  // Actual, luego de leer car
 	ch           byte // Char actual
+	line         int  // Linea del char actual
+	column       int  // Columna del char actual
+	comments     []Comment
+}
+
+// Comment es un comentario de linea (// ...). El lexer no lo entrega como
+// token, pero lo guarda para herramientas como el formateador.
+type Comment struct {
+	Text   string
+	Line   int
+	Column int
 }
 
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	l.skipWhitespace()
+	for l.ch == '/' && l.peekChar() == '/' {
+		l.readComment()
+		l.skipWhitespace()
+	}
+	line, column := l.line, l.column
 
 	switch l.ch {
 
@@ -76,10 +93,30 @@ func (l *Lexer) NextToken() token.Token {
 
 	case ':':
 		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
+	case '?':
+		switch l.peekChar() {
+		case '?':
+			l.readChar()
+			tok = token.Token{Type: token.NULLISH, Literal: "??"}
+		case '[':
+			l.readChar()
+			tok = token.Token{Type: token.OPT_LBRACKET, Literal: "?["}
+		default:
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
 	default:
 		if esLetra(l.ch) {
 			tok.Literal = l.readIdentificador()
 			tok.Type = token.CheckIdentificador(tok.Literal)
+			tok.Line, tok.Column = line, column
 			return tok
 		} else if esDigito(l.ch) {
 			tok.Literal = l.readNumero()
@@ -94,12 +131,14 @@ func (l *Lexer) NextToken() token.Token {
 			} else {
 				tok.Type = token.INT
 			}
+			tok.Line, tok.Column = line, column
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
 		}
 	}
 	l.readChar()
+	tok.Line, tok.Column = line, column
 	return tok
 }
 
@@ -131,6 +170,21 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
+func (l *Lexer) readComment() {
+	comment := Comment{Line: l.line, Column: l.column}
+	position := l.position
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	comment.Text = strings.TrimRight(l.input[position:l.position], " \t\r")
+	l.comments = append(l.comments, comment)
+}
+
+// Comments devuelve los comentarios leidos hasta ahora, en orden.
+func (l *Lexer) Comments() []Comment {
+	return l.comments
+}
+
 func (l *Lexer) readIdentificador() string {
 	position := l.position
 	for esLetra(l.ch) {
@@ -148,6 +202,11 @@ func newToken(tipoToken token.TokenType, caracter byte) token.Token {
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // ASCII de nulo o fin de archivo
 	} else {
@@ -171,7 +230,7 @@ func (l *Lexer) readString() string {
 // -------------------------REPL -------------------------------------
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
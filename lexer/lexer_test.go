@@ -119,3 +119,120 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "enchanted x = 5;\n  x + 10.5"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"enchanted", 1, 1},
+		{"x", 1, 11},
+		{"=", 1, 13},
+		{"5", 1, 15},
+		{";", 1, 16},
+		{"x", 2, 3},
+		{"+", 2, 5},
+		{"10.5", 2, 7},
+		{"", 2, 11},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Tipo erroneo de literal. Esperaba %q, obtuvo %q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - Posicion erronea para %q. Esperaba %d:%d, obtuvo %s",
+				i, tt.expectedLiteral, tt.expectedLine, tt.expectedColumn, tok.Position())
+		}
+	}
+}
+
+func TestOperatorTokens(t *testing.T) {
+	input := `BlankSpace ?? h?["k"] ? 5 ...rest .`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.NULL, "BlankSpace"},
+		{token.NULLISH, "??"},
+		{token.ID, "h"},
+		{token.OPT_LBRACKET, "?["},
+		{token.STRING, "k"},
+		{token.RBRACKET, "]"},
+		{token.ILLEGAL, "?"},
+		{token.INT, "5"},
+		{token.ELLIPSIS, "..."},
+		{token.ID, "rest"},
+		{token.ILLEGAL, "."},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Tipo erroneo de token. Esperaba %q, obtuvo %q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Tipo erroneo de literal. Esperaba %q, obtuvo %q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := "// inicio\nenchanted x = 10 / 2; // mitad  \n//\nx"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "enchanted"},
+		{token.ID, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "10"},
+		{token.DIVIDES, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.ID, "x"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token erroneo. Esperaba %q %q, obtuvo %q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+
+	expected := []Comment{
+		{Text: "// inicio", Line: 1, Column: 1},
+		{Text: "// mitad", Line: 2, Column: 23},
+		{Text: "//", Line: 3, Column: 1},
+	}
+	comments := l.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("se esperaban %d comentarios, se obtuvieron %d: %+v", len(expected), len(comments), comments)
+	}
+	for i, c := range expected {
+		if comments[i] != c {
+			t.Errorf("comments[%d] erroneo. Esperaba %+v, obtuvo %+v", i, c, comments[i])
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"main/ast"
+	"main/lexer"
+	"main/parser"
+	"main/token"
+	"sort"
+	"strings"
+)
+
+type Severity int
+
+const (
+	Off Severity = iota
+	Info
+	Warning
+	Error
+)
+
+var severityNames = map[Severity]string{
+	Off:     "off",
+	Info:    "info",
+	Warning: "warning",
+	Error:   "error",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+func ParseSeverity(name string) (Severity, error) {
+	for severity, n := range severityNames {
+		if n == name {
+			return severity, nil
+		}
+	}
+	return Off, fmt.Errorf("severidad desconocida: %q", name)
+}
+
+// Diagnostic es un problema encontrado por una regla.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", d.Line, d.Column, d.Severity, d.Message, d.Rule)
+}
+
+// Config dice la severidad de cada regla; Off la desactiva.
+type Config map[string]Severity
+
+// DefaultConfig activa todas las reglas con su severidad por defecto.
+func DefaultConfig() Config {
+	config := Config{}
+	for _, r := range rules {
+		config[r.name] = r.severity
+	}
+	return config
+}
+
+// ParseConfig aplica sobre DefaultConfig una lista como
+// "unused-binding=off,constant-condition=error".
+func ParseConfig(spec string) (Config, error) {
+	config := DefaultConfig()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("se esperaba regla=severidad, se obtuvo: %q", entry)
+		}
+		name := strings.TrimSpace(parts[0])
+		if _, ok := config[name]; !ok {
+			return nil, fmt.Errorf("regla desconocida: %q", name)
+		}
+		severity, err := ParseSeverity(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		config[name] = severity
+	}
+	return config, nil
+}
+
+// RuleNames devuelve los nombres de todas las reglas.
+func RuleNames() []string {
+	names := []string{}
+	for _, r := range rules {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// Source parsea src y lo revisa, respetando los comentarios lint:ignore.
+func Source(src string, config Config) ([]Diagnostic, error) {
+	l := lexer.New(src)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+	return Lint(program, l.Comments(), config), nil
+}
+
+// Lint corre las reglas activas de config sobre el programa. Un comentario
+// `// lint:ignore` silencia su misma linea y la siguiente; si lleva nombres
+// de reglas, solo esas.
+func Lint(program *ast.Program, comments []lexer.Comment, config Config) []Diagnostic {
+	l := &linter{config: config}
+	for _, r := range rules {
+		if config[r.name] == Off {
+			continue
+		}
+		l.rule = r.name
+		r.check(l, program)
+	}
+
+	ignores := parseIgnores(comments)
+	diagnostics := []Diagnostic{}
+	for _, d := range l.diagnostics {
+		if !ignores.match(d) {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return diagnostics
+}
+
+type linter struct {
+	config      Config
+	rule        string // Regla que esta corriendo
+	diagnostics []Diagnostic
+	scopes      *scopeInfo
+}
+
+func (l *linter) report(tok token.Token, format string, a ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Rule:     l.rule,
+		Severity: l.config[l.rule],
+		Line:     tok.Line,
+		Column:   tok.Column,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// ---------------------------Suppression--------------------------------
+
+const ignoreDirective = "lint:ignore"
+
+// ignores guarda, por linea, las reglas silenciadas; un set vacio las
+// silencia todas.
+type ignores map[int]map[string]bool
+
+func parseIgnores(comments []lexer.Comment) ignores {
+	result := ignores{}
+	for _, c := range comments {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if !strings.HasPrefix(text, ignoreDirective) {
+			continue
+		}
+		rules := map[string]bool{}
+		fields := strings.FieldsFunc(text[len(ignoreDirective):], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		for _, name := range fields {
+			rules[name] = true
+		}
+		result.add(c.Line, rules)
+		result.add(c.Line+1, rules)
+	}
+	return result
+}
+
+func (ig ignores) add(line int, rules map[string]bool) {
+	current, ok := ig[line]
+	switch {
+	case !ok:
+		ig[line] = rules
+	case len(current) == 0 || len(rules) == 0:
+		ig[line] = map[string]bool{}
+	default:
+		merged := map[string]bool{}
+		for name := range current {
+			merged[name] = true
+		}
+		for name := range rules {
+			merged[name] = true
+		}
+		ig[line] = merged
+	}
+}
+
+func (ig ignores) match(d Diagnostic) bool {
+	rules, ok := ig[d.Line]
+	return ok && (len(rules) == 0 || rules[d.Rule])
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"testing"
+)
+
+func testLint(t *testing.T, input string, config Config) []string {
+	diagnostics, err := Source(input, config)
+	if err != nil {
+		t.Fatalf("errores al parsear %q: %v", input, err)
+	}
+	result := []string{}
+	for _, d := range diagnostics {
+		result = append(result, d.String())
+	}
+	return result
+}
+
+func only(rule string) Config {
+	config := Config{}
+	for _, name := range RuleNames() {
+		config[name] = Off
+	}
+	config[rule] = DefaultConfig()[rule]
+	return config
+}
+
+func checkDiagnostics(t *testing.T, input string, got, expected []string) {
+	if len(got) != len(expected) {
+		t.Errorf("%q: se esperaba %q, se obtuvo %q", input, expected, got)
+		return
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("%q: diagnostico erroneo. Esperaba %q, obtuvo %q", input, expected[i], got[i])
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule     string
+		input    string
+		expected []string
+	}{
+		{"unused-binding", "enchanted x = 1; enchanted y = 2; y",
+			[]string{"1:11: warning: La variable x se declara pero no se usa (unused-binding)"}},
+		{"unused-binding", "enchanted [a, ...b] = [1]; a", []string{
+			"1:18: warning: La variable b se declara pero no se usa (unused-binding)"}},
+		{"unused-binding", "enchanted _x = 1; isme(p) { 1 }", nil},
+		{"unused-binding", "enchanted f = isme() { g() }; enchanted g = isme() { 1 }; f()", nil},
+		{"unused-binding", "enchanted f = isme() { enchanted t = 1; 2 }; f()",
+			[]string{"1:34: warning: La variable t se declara pero no se usa (unused-binding)"}},
+
+		{"shadowed-parameter", "enchanted x = 1; enchanted f = isme(x) { x }; f(x)",
+			[]string{"1:37: info: El parametro x oculta la variable declarada en 1:11 (shadowed-parameter)"}},
+		{"shadowed-parameter", "isme(a) { isme([b, a]) { a } }",
+			[]string{"1:20: info: El parametro a oculta la variable declarada en 1:6 (shadowed-parameter)"}},
+		{"shadowed-parameter", "isme(a) { a }; isme(a) { a }", nil},
+
+		{"unreachable-code", "isme() { hi 1; 2; 3 }",
+			[]string{"1:16: error: Codigo inalcanzable despues de hi (unreachable-code)"}},
+		{"unreachable-code", "hi 1;\nenchanted x = 2;",
+			[]string{"2:1: error: Codigo inalcanzable despues de hi (unreachable-code)"}},
+		{"unreachable-code", "isme(x) { LoverEra (x) { hi 1 } RepEra { hi 2 }; x }",
+			[]string{"1:50: error: Codigo inalcanzable despues de hi (unreachable-code)"}},
+		{"unreachable-code", "isme(x) { LoverEra (x) { hi 1 }; x }", nil},
+		{"unreachable-code", "isme(x) { hi 1; LoverEra (x) { 2 }; 3 }",
+			[]string{"1:17: error: Codigo inalcanzable despues de hi (unreachable-code)"}},
+
+		{"constant-condition", "LoverEra (1 < 2) { 1 }",
+			[]string{"1:1: warning: La condicion del LoverEra es constante: (1 < 2) (constant-condition)"}},
+		{"constant-condition", "LoverEra (!SparksFly) { 1 } RepEra { LoverEra (BlankSpace) { 2 } }", []string{
+			"1:1: warning: La condicion del LoverEra es constante: (!SparksFly) (constant-condition)",
+			"1:38: warning: La condicion del LoverEra es constante: BlankSpace (constant-condition)",
+		}},
+		{"constant-condition", "enchanted x = 1; LoverEra (x < 2) { 1 }", nil},
+	}
+
+	for _, tt := range tests {
+		checkDiagnostics(t, tt.input, testLint(t, tt.input, only(tt.rule)), tt.expected)
+	}
+}
+
+func TestSuppression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"enchanted x = 1; // lint:ignore", nil},
+		{"// lint:ignore unused-binding\nenchanted x = 1;", nil},
+		{"// lint:ignore constant-condition\nenchanted x = 1;",
+			[]string{"2:11: warning: La variable x se declara pero no se usa (unused-binding)"}},
+		{"// lint:ignore unused-binding, constant-condition\nenchanted x = LoverEra (1) { 2 };", nil},
+		{"// lint:ignore\n\nenchanted x = 1;",
+			[]string{"3:11: warning: La variable x se declara pero no se usa (unused-binding)"}},
+	}
+
+	for _, tt := range tests {
+		checkDiagnostics(t, tt.input, testLint(t, tt.input, DefaultConfig()), tt.expected)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	input := "enchanted x = 1; LoverEra (SparksFly) { 2 }"
+
+	config, err := ParseConfig("unused-binding=off, constant-condition=error")
+	if err != nil {
+		t.Fatalf("ParseConfig fallo: %v", err)
+	}
+	checkDiagnostics(t, input, testLint(t, input, config), []string{
+		"1:18: error: La condicion del LoverEra es constante: SparksFly (constant-condition)",
+	})
+
+	checkDiagnostics(t, input, testLint(t, input, DefaultConfig()), []string{
+		"1:11: warning: La variable x se declara pero no se usa (unused-binding)",
+		"1:18: warning: La condicion del LoverEra es constante: SparksFly (constant-condition)",
+	})
+
+	for _, spec := range []string{"nada=off", "unused-binding=fuerte", "unused-binding"} {
+		if _, err := ParseConfig(spec); err == nil {
+			t.Errorf("se esperaba un error para %q", spec)
+		}
+	}
+}
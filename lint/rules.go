@@ -0,0 +1,196 @@
+package lint
+
+import (
+	"main/ast"
+	"main/cfg"
+	"main/token"
+	"strings"
+)
+
+type rule struct {
+	name     string
+	severity Severity // Severidad por defecto
+	check    func(l *linter, program *ast.Program)
+}
+
+var rules = []rule{
+	{"unused-binding", Warning, checkUnusedBindings},
+	{"shadowed-parameter", Info, checkShadowedParameters},
+	{"unreachable-code", Error, checkUnreachableCode},
+	{"constant-condition", Warning, checkConstantConditions},
+}
+
+// ---------------------------unused-binding--------------------------------
+
+// checkUnusedBindings reporta los enchanted cuyo nombre nunca se lee. Los
+// nombres que empiezan con _ se dejan sin usar a proposito.
+func checkUnusedBindings(l *linter, program *ast.Program) {
+	for _, b := range l.scopeInfo(program).bindings {
+		if b.param || b.used || strings.HasPrefix(b.decl.Value, "_") {
+			continue
+		}
+		l.report(b.decl.Token, "La variable %s se declara pero no se usa", b.decl.Value)
+	}
+}
+
+// ---------------------------shadowed-parameter--------------------------------
+
+func checkShadowedParameters(l *linter, program *ast.Program) {
+	for _, s := range l.scopeInfo(program).shadows {
+		l.report(s.param.Token, "El parametro %s oculta la variable declarada en %s",
+			s.param.Value, s.outer.Token.Position())
+	}
+}
+
+// ---------------------------unreachable-code--------------------------------
+
+// checkUnreachableCode reporta el comienzo de cada bloque del grafo de flujo
+// al que no llega nadie: lo que viene despues de un hi, o de un LoverEra
+// cuyas dos ramas terminan en hi. Lo que cuelga de ese bloque ya queda
+// cubierto por el mismo reporte.
+func checkUnreachableCode(l *linter, program *ast.Program) {
+	for _, g := range cfg.Build(program) {
+		for _, b := range g.Unreachable() {
+			if len(b.Preds) > 0 {
+				continue
+			}
+			var tok token.Token
+			if len(b.Stmts) > 0 {
+				tok = ast.TokenOf(b.Stmts[0])
+			} else {
+				tok = b.Cond.Token
+			}
+			l.report(tok, "Codigo inalcanzable despues de hi")
+		}
+	}
+}
+
+// ---------------------------constant-condition--------------------------------
+
+func checkConstantConditions(l *linter, program *ast.Program) {
+	ast.Inspect(program, func(n ast.Node) bool {
+		if ifExp, ok := n.(*ast.IfExpression); ok && isConstant(ifExp.Condition) {
+			l.report(ifExp.Token, "La condicion del LoverEra es constante: %s", ifExp.Condition.String())
+		}
+		return true
+	})
+}
+
+// isConstant dice si e solo depende de literales.
+func isConstant(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.Boolean, *ast.NullLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return isConstant(e.Right)
+	case *ast.InfixExpression:
+		return isConstant(e.Left) && isConstant(e.Right)
+	}
+	return false
+}
+
+// ---------------------------Scopes--------------------------------
+
+// scopeInfo es lo que comparten unused-binding y shadowed-parameter: cada
+// nombre declarado, si se leyo, y los parametros que ocultan otro nombre.
+type scopeInfo struct {
+	bindings []*binding
+	shadows  []shadow
+}
+
+type binding struct {
+	decl  *ast.Variable
+	param bool
+	used  bool
+}
+
+type shadow struct {
+	param *ast.Variable
+	outer *ast.Variable
+}
+
+// lintScope es un scope de funcion; igual que en el evaluador, los bloques
+// no abren uno nuevo.
+type lintScope struct {
+	outer *lintScope
+	names map[string][]*binding
+}
+
+func (l *linter) scopeInfo(program *ast.Program) *scopeInfo {
+	if l.scopes == nil {
+		l.scopes = &scopeInfo{}
+		global := &lintScope{names: map[string][]*binding{}}
+		l.scopes.hoist(global, program)
+		l.scopes.walk(global, program)
+	}
+	return l.scopes
+}
+
+func (info *scopeInfo) declare(s *lintScope, v *ast.Variable, param bool) {
+	b := &binding{decl: v, param: param}
+	s.names[v.Value] = append(s.names[v.Value], b)
+	info.bindings = append(info.bindings, b)
+}
+
+// hoist declara los enchanted de un scope antes de recorrerlo, porque una
+// funcion puede leer un nombre que se define despues.
+func (info *scopeInfo) hoist(s *lintScope, node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FunctionLiteral, *ast.MacroLiteral:
+			return false
+		case *ast.LetStatement:
+			for _, v := range ast.PatternVariables(n.Target()) {
+				info.declare(s, v, false)
+			}
+		}
+		return true
+	})
+}
+
+func (info *scopeInfo) walk(s *lintScope, node ast.Node) {
+	switch n := node.(type) {
+	case *ast.LetStatement:
+		if n.Value != nil {
+			info.walk(s, n.Value)
+		}
+	case *ast.FunctionLiteral:
+		params := []*ast.Variable{}
+		for _, p := range n.Parameters {
+			params = append(params, ast.PatternVariables(p)...)
+		}
+		info.function(s, params, n.Body)
+	case *ast.MacroLiteral:
+		info.function(s, n.Parameters, n.Body)
+	case *ast.Variable:
+		for scope := s; scope != nil; scope = scope.outer {
+			if bindings, ok := scope.names[n.Value]; ok {
+				for _, b := range bindings {
+					b.used = true
+				}
+				return
+			}
+		}
+	default:
+		for _, child := range ast.Children(node) {
+			info.walk(s, child)
+		}
+	}
+}
+
+func (info *scopeInfo) function(outer *lintScope, params []*ast.Variable, body *ast.BlockStatement) {
+	s := &lintScope{outer: outer, names: map[string][]*binding{}}
+	for _, p := range params {
+		for scope := outer; scope != nil; scope = scope.outer {
+			if bindings, ok := scope.names[p.Value]; ok {
+				info.shadows = append(info.shadows, shadow{param: p, outer: bindings[0].decl})
+				break
+			}
+		}
+		info.declare(s, p, true)
+	}
+	if body != nil {
+		info.hoist(s, body)
+		info.walk(s, body)
+	}
+}
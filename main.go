@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/user"
@@ -9,6 +10,43 @@ import (
 )
 
 func main() {
+	dumpAST := flag.Bool("ast-json", false, "imprime el AST del archivo en JSON y termina")
+	cfgImage := flag.String("cfg", "", "dibuja el grafo de flujo de control del archivo en esta imagen PNG y termina")
+	format := flag.Bool("fmt", false, "formatea los archivos dados y termina")
+	check := flag.Bool("check", false, "con -fmt, solo lista los archivos sin formato y sale con error si hay alguno")
+	lintFiles := flag.Bool("lint", false, "revisa los archivos dados con el linter y termina")
+	rules := flag.String("rules", "", "con -lint, severidad por regla: unused-binding=off,constant-condition=error")
+	flag.Parse()
+
+	if *lintFiles {
+		if !repl.LintFiles(flag.Args(), *rules, os.Stdout) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *format {
+		if !repl.FormatFiles(flag.Args(), *check, os.Stdout) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	filePath := "main3.sp"
+	if flag.NArg() > 0 {
+		filePath = flag.Arg(0)
+	}
+
+	if *dumpAST {
+		repl.DumpAST(filePath, os.Stdout)
+		return
+	}
+
+	if *cfgImage != "" {
+		repl.DumpCFG(filePath, *cfgImage, os.Stdout)
+		return
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -16,5 +54,5 @@ func main() {
 	fmt.Printf("¡Bienvenido %s! al primer lenguaje de programación de Taylor Swift!\n",
 		user.Username)
 	fmt.Printf("Burn some commands\n")
-	repl.Start("main3.sp", os.Stdout)
+	repl.Start(filePath, os.Stdout)
 }
@@ -1,19 +1,29 @@
 package object
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
-	env.outer = outer
-	return env
+	s := make(map[string]Object)
+	return &Environment{store: s, outer: outer, runtime: outer.runtime}
 }
 
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	return &Environment{store: s, outer: nil, runtime: NewRuntime()}
+}
+
+// NewModuleEnvironment crea el entorno global de un modulo importado. No
+// ve los nombres del importador pero comparte su Runtime.
+func NewModuleEnvironment(importer *Environment, file string) *Environment {
+	s := make(map[string]Object)
+	return &Environment{store: s, runtime: importer.runtime, file: file}
 }
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store   map[string]Object
+	slots   []Object // Los mismos valores, por el indice que da el resolver
+	slotOf  map[string]int
+	outer   *Environment
+	runtime *Runtime
+	file    string // Archivo .sp del que salen los nombres, si hay
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -23,7 +33,64 @@ func (e *Environment) Get(name string) (Object, bool) {
 	}
 	return obj, ok
 }
+
+// Set liga name con val; si name ya tiene slot, tambien lo actualiza.
 func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
+	if slot, ok := e.slotOf[name]; ok {
+		e.slots[slot] = val
+	}
 	return val
 }
+
+// GetAt busca el valor del slot en el entorno que esta depth niveles hacia
+// afuera. Devuelve false si ese slot todavia no tiene valor.
+func (e *Environment) GetAt(depth, slot int) (Object, bool) {
+	env := e
+	for i := 0; i < depth && env != nil; i++ {
+		env = env.outer
+	}
+	if env == nil || slot >= len(env.slots) || env.slots[slot] == nil {
+		return nil, false
+	}
+	return env.slots[slot], true
+}
+
+// SetAt guarda val en el slot y tambien por nombre, para que Get y Names lo
+// sigan viendo.
+func (e *Environment) SetAt(slot int, name string, val Object) Object {
+	for slot >= len(e.slots) {
+		e.slots = append(e.slots, nil)
+	}
+	if e.slotOf == nil {
+		e.slotOf = map[string]int{}
+	}
+	e.slotOf[name] = slot
+	return e.Set(name, val)
+}
+
+// Names devuelve los nombres definidos directamente en este entorno.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (e *Environment) Runtime() *Runtime {
+	return e.runtime
+}
+
+// File devuelve el archivo del modulo al que pertenece el entorno, buscando
+// en los entornos externos; es "" si el programa no viene de un archivo.
+func (e *Environment) File() string {
+	if e.file == "" && e.outer != nil {
+		return e.outer.File()
+	}
+	return e.file
+}
+
+func (e *Environment) SetFile(file string) {
+	e.file = file
+}
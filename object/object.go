@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"main/ast"
+	"math/big"
 	"strings"
 )
 
@@ -17,6 +18,7 @@ type Object interface {
 
 const (
 	INTEGER_OBJ  = "INTEGER"
+	BIGINT_OBJ   = "BIGINT"
 	FLOAT_OBJ    = "FLOAT"
 	BOOL_OBJ     = "BOOL"
 	NULL_OBJ     = "NULL"
@@ -27,11 +29,90 @@ const (
 	BUILTIN_OBJ  = "BUILTIN"
 	ARRAY_OBJ    = "ARRAY"
 	HASH_OBJ     = "HASH"
+	MODULE_OBJ   = "MODULE"
+	QUOTE_OBJ    = "QUOTE"
+	MACRO_OBJ    = "MACRO"
 )
 
-type BuiltinFunction func(args ...Object) Object
+// BuiltinFunction recibe el Runtime de quien la llama, para usar sus
+// entradas y salidas.
+type BuiltinFunction func(rt *Runtime, args ...Object) Object
 type Builtin struct {
-	Fn BuiltinFunction
+	Name      string
+	Signature Signature
+	Fn        BuiltinFunction
+}
+
+// Param es un argumento de un builtin. Types son los tipos que acepta; si
+// esta vacio acepta cualquiera.
+type Param struct {
+	Name  string
+	Types []ObjectType
+}
+
+func (p Param) Accepts(t ObjectType) bool {
+	if len(p.Types) == 0 {
+		return true
+	}
+	for _, accepted := range p.Types {
+		if accepted == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Signature declara los argumentos de un builtin. Si Variadic, el ultimo
+// parametro se repite cero o mas veces, como en SpeakNow.
+//
+// Type es el tipo del builtin para el typechecker, escrito como el los
+// imprime: "isme([a], isme(a) b) [b]" es el de map. Una letra sola es una
+// variable de tipo. Vacio, el builtin recibe y devuelve any.
+type Signature struct {
+	Params   []Param
+	Variadic bool
+	Type     string
+}
+
+// ArityError devuelve el mensaje para una llamada con n argumentos, o "" si
+// n es valido.
+func (b *Builtin) ArityError(n int) string {
+	params := len(b.Signature.Params)
+	if b.Signature.Variadic {
+		if n >= params-1 {
+			return ""
+		}
+		return fmt.Sprintf("Numero equivocado de argumentos para `%s`. Son: %d, deberian ser al menos %d",
+			b.Name, n, params-1)
+	}
+	if n == params {
+		return ""
+	}
+	return fmt.Sprintf("Numero equivocado de argumentos para `%s`. Son: %d, deberian ser %d",
+		b.Name, n, params)
+}
+
+// TypeError devuelve el mensaje para el argumento i (desde 0) de tipo t, o
+// "" si el parametro lo acepta.
+func (b *Builtin) TypeError(i int, t ObjectType) string {
+	param := b.param(i)
+	if param.Accepts(t) {
+		return ""
+	}
+	types := make([]string, len(param.Types))
+	for j, accepted := range param.Types {
+		types[j] = string(accepted)
+	}
+	return fmt.Sprintf("Tipo sin soporte para `%s`: el argumento %s deberia ser %s, no %s",
+		b.Name, param.Name, strings.Join(types, " o "), t)
+}
+
+func (b *Builtin) param(i int) Param {
+	params := b.Signature.Params
+	if i >= len(params) {
+		return params[len(params)-1]
+	}
+	return params[i]
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -44,6 +125,16 @@ type Integer struct {
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 
+// BigInt es un entero que no entra en int64. El evaluador pasa a BigInt
+// cuando una operacion entre enteros se desborda, y vuelve a Integer cuando
+// el resultado entra: un BigInt nunca tiene un valor que quepa en Integer.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+
 type Float struct {
 	Value float64
 }
@@ -71,17 +162,75 @@ type ReturnVal struct {
 func (rv *ReturnVal) Type() ObjectType { return RETURN_OBJ }
 func (rv *ReturnVal) Inspect() string  { return rv.Value.Inspect() }
 
+// ErrorKind distingue los errores del programa de los que produce el
+// Runtime al cortar una ejecucion que se paso de sus limites.
+type ErrorKind int
+
+const (
+	ProgramError ErrorKind = iota
+	StepLimitError
+	DepthLimitError
+	AllocationLimitError
+	CanceledError
+)
+
 type Error struct {
 	Message string
+	Kind    ErrorKind
+	// Position es "linea:columna" del nodo que fallo; vacio si el error no
+	// vino de evaluar codigo o si es de un limite.
+	Position string
+	// Stack son las llamadas en curso cuando ocurrio el error, de la mas
+	// interna a la mas externa.
+	Stack []Frame
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
+// maxTraceFrames es cuantas llamadas muestra Traceback de cada punta de la
+// pila; las del medio se resumen, como en una recursion infinita.
+const maxTraceFrames = 10
+
+// Traceback devuelve el error con una llamada por linea, de la mas interna
+// a la mas externa.
+func (e *Error) Traceback() string {
+	var out bytes.Buffer
+	out.WriteString("ERROR: ")
+	if e.Position != "" {
+		out.WriteString(e.Position + ": ")
+	}
+	out.WriteString(e.Message)
+	for i, frame := range e.Stack {
+		if len(e.Stack) > 2*maxTraceFrames && i == maxTraceFrames {
+			fmt.Fprintf(&out, "\n\t... %d llamadas mas", len(e.Stack)-2*maxTraceFrames)
+		}
+		if len(e.Stack) > 2*maxTraceFrames && i >= maxTraceFrames && i < len(e.Stack)-maxTraceFrames {
+			continue
+		}
+		out.WriteString("\n\ten " + frame.String())
+	}
+	return out.String()
+}
+
+// Frame es una llamada en curso: la funcion y donde se la llamo.
+type Frame struct {
+	Function string
+	Position string // "linea:columna" de la llamada; vacio si vino de Go
+}
+
+func (f Frame) String() string {
+	if f.Position == "" {
+		return f.Function
+	}
+	return f.Function + ", llamada en " + f.Position
+}
+
 type Function struct {
-	Parameters []*ast.Variable
+	Parameters []ast.Pattern
 	Body       *ast.BlockStatement
 	Env        *Environment
+	Name       string // El primer nombre con que se la ligo, para las trazas
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -141,6 +290,11 @@ func (b *Bool) HashKey() HashKey {
 func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Value))
@@ -151,9 +305,57 @@ type HashPair struct {
 	Key   Object
 	Value Object
 }
+
+// Hash guarda sus pares en un map para buscarlos en O(1) y recuerda el orden
+// de insercion en Order para recorrerlos e imprimirlos siempre igual.
 type Hash struct {
 	Pairs map[HashKey]HashPair
+	Order []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set agrega o reemplaza un par. Reemplazar conserva la posicion original.
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if _, ok := h.Pairs[key]; !ok {
+		h.Order = append(h.Order, key)
+	}
+	h.Pairs[key] = pair
 }
+
+func (h *Hash) Get(key HashKey) (HashPair, bool) {
+	pair, ok := h.Pairs[key]
+	return pair, ok
+}
+
+func (h *Hash) Delete(key HashKey) {
+	if _, ok := h.Pairs[key]; !ok {
+		return
+	}
+	delete(h.Pairs, key)
+	for i, k := range h.Order {
+		if k == key {
+			h.Order = append(h.Order[:i:i], h.Order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (h *Hash) Len() int {
+	return len(h.Order)
+}
+
+// Ordered devuelve los pares en orden de insercion.
+func (h *Hash) Ordered() []HashPair {
+	pairs := make([]HashPair, 0, len(h.Order))
+	for _, key := range h.Order {
+		pairs = append(pairs, h.Pairs[key])
+	}
+	return pairs
+}
+
 type Hashable interface {
 	HashKey() HashKey
 }
@@ -162,7 +364,7 @@ func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.Ordered() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
 			pair.Key.Inspect(), pair.Value.Inspect()))
 	}
@@ -171,3 +373,44 @@ func (h *Hash) Inspect() string {
 	out.WriteString("}")
 	return out.String()
 }
+
+// Module expone los nombres globales de un archivo importado con `feat`.
+type Module struct {
+	Path string
+	Env  *Environment
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "module(" + m.Path + ")" }
+
+// Quote guarda un nodo del AST sin evaluar, producido por `quote`.
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType { return QUOTE_OBJ }
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+type Macro struct {
+	Parameters []*ast.Variable
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (m *Macro) Type() ObjectType { return MACRO_OBJ }
+func (m *Macro) Inspect() string {
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+	out.WriteString("folklore")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String())
+	out.WriteString("\n}")
+	return out.String()
+}
@@ -0,0 +1,178 @@
+package object
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// Runtime guarda el estado compartido por todos los entornos de una misma
+// ejecucion, incluidos los de los modulos importados.
+type Runtime struct {
+	// Modules cachea los modulos ya evaluados por ruta absoluta.
+	Modules map[string]*Module
+	// Importing es la pila de modulos que se estan evaluando, para detectar
+	// importaciones circulares.
+	Importing []string
+
+	// Stdout y Stderr son a donde escriben los builtins.
+	Stdout io.Writer
+	Stderr io.Writer
+	stdin  *bufio.Reader // nil hasta que input lea de os.Stdin
+	rand   *rand.Rand    // nil hasta el primer numero al azar o Seed
+
+	Limits      Limits
+	stack       []Frame
+	ctx         context.Context
+	steps       int
+	depth       int
+	allocations int
+}
+
+// Limits son los presupuestos de una ejecucion. Cero es sin limite, salvo
+// MaxDepth, que usa DefaultMaxDepth: sin tope una recursion infinita agota
+// la pila de Go y tira abajo el proceso.
+type Limits struct {
+	MaxSteps       int // Nodos evaluados
+	MaxDepth       int // Llamadas a funciones anidadas
+	MaxAllocations int // Objetos creados; los arrays y hashMaps suman tambien sus elementos, y los strings su largo
+}
+
+const DefaultMaxDepth = 10000
+
+// StringChunk es cuantos bytes de un string cuentan como un objeto en
+// MaxAllocations, para que un string enorme no valga lo mismo que uno corto.
+const StringChunk = 64
+
+// contextCheckInterval es cada cuantos pasos se revisa si el contexto se
+// cancelo.
+const contextCheckInterval = 256
+
+func NewRuntime() *Runtime {
+	return &Runtime{
+		Modules: make(map[string]*Module),
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+}
+
+// SetStdin cambia de donde lee el builtin input.
+func (rt *Runtime) SetStdin(r io.Reader) {
+	rt.stdin = bufio.NewReader(r)
+}
+
+// Rand es el generador de los builtins random y randomInt. Sin Seed arranca
+// con la hora, asi que cada ejecucion da numeros distintos.
+func (rt *Runtime) Rand() *rand.Rand {
+	if rt.rand == nil {
+		rt.Seed(time.Now().UnixNano())
+	}
+	return rt.rand
+}
+
+// Seed reinicia el generador para que repita la misma secuencia.
+func (rt *Runtime) Seed(seed int64) {
+	rt.rand = rand.New(rand.NewSource(seed))
+}
+
+// ReadLine lee una linea de la entrada, sin el salto de linea. Devuelve
+// io.EOF solo si ya no quedaba nada por leer.
+func (rt *Runtime) ReadLine() (string, error) {
+	if rt.stdin == nil {
+		rt.stdin = bufio.NewReader(os.Stdin)
+	}
+	line, err := rt.stdin.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// Begin empieza a contar los presupuestos desde cero para una ejecucion que
+// se corta si ctx se cancela. ctx puede ser nil.
+func (rt *Runtime) Begin(ctx context.Context) {
+	rt.ctx = ctx
+	rt.steps, rt.depth, rt.allocations = 0, 0, 0
+	rt.stack = rt.stack[:0]
+}
+
+// PushFrame anota que empezo una llamada; cada PushFrame lleva su PopFrame.
+func (rt *Runtime) PushFrame(frame Frame) {
+	rt.stack = append(rt.stack, frame)
+}
+
+func (rt *Runtime) PopFrame() {
+	rt.stack = rt.stack[:len(rt.stack)-1]
+}
+
+// Trace copia las llamadas en curso, de la mas interna a la mas externa.
+func (rt *Runtime) Trace() []Frame {
+	trace := make([]Frame, len(rt.stack))
+	for i, frame := range rt.stack {
+		trace[len(rt.stack)-1-i] = frame
+	}
+	return trace
+}
+
+// Step cuenta un paso de evaluacion.
+func (rt *Runtime) Step() *Error {
+	rt.steps++
+	if rt.Limits.MaxSteps > 0 && rt.steps > rt.Limits.MaxSteps {
+		return &Error{Kind: StepLimitError,
+			Message: fmt.Sprintf("Se supero el limite de %d pasos", rt.Limits.MaxSteps)}
+	}
+	if rt.ctx != nil && rt.steps%contextCheckInterval == 0 {
+		if err := rt.ctx.Err(); err != nil {
+			return &Error{Kind: CanceledError, Message: "Ejecucion cancelada: " + err.Error()}
+		}
+	}
+	return nil
+}
+
+// Enter cuenta una llamada a funcion; cada Enter sin error lleva su Leave.
+func (rt *Runtime) Enter() *Error {
+	max := rt.Limits.MaxDepth
+	if max <= 0 {
+		max = DefaultMaxDepth
+	}
+	if rt.depth >= max {
+		return &Error{Kind: DepthLimitError,
+			Message: fmt.Sprintf("Se supero la profundidad maxima de %d llamadas", max)}
+	}
+	rt.depth++
+	return nil
+}
+
+func (rt *Runtime) Leave() {
+	rt.depth--
+}
+
+// Allocate cuenta los objetos que crea obj. BadBlood, SparksFly y BlankSpace
+// son siempre los mismos y no cuentan.
+func (rt *Runtime) Allocate(obj Object) *Error {
+	switch obj := obj.(type) {
+	case *Bool, *Null, *Error, nil:
+		return nil
+	case *String:
+		rt.allocations += 1 + len(obj.Value)/StringChunk
+	case *Array:
+		rt.allocations += 1 + len(obj.Elements)
+	case *Hash:
+		rt.allocations += 1 + obj.Len()
+	default:
+		rt.allocations++
+	}
+	if rt.Limits.MaxAllocations > 0 && rt.allocations > rt.Limits.MaxAllocations {
+		return &Error{Kind: AllocationLimitError,
+			Message: fmt.Sprintf("Se supero el limite de %d objetos", rt.Limits.MaxAllocations)}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package optimizer
+
+import (
+	"main/ast"
+	"main/evaluator"
+	"main/object"
+	"main/token"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Optimize simplifica el AST antes de evaluarlo o compilarlo:
+//
+//   - calcula las expresiones que solo usan literales, con el mismo evaluador
+//     que las correria; si dan error se dejan, para que el error salga al
+//     ejecutar;
+//   - quita las identidades x * 1, x / 1, x - 0 y x + 0 cuando x es un
+//     numero seguro (ver numeric);
+//   - poda los LoverEra cuya condicion es constante.
+//
+// Igual que ast.Modify, cambia node en su lugar.
+func Optimize(node ast.Node) ast.Node {
+	return ast.Modify(node, optimize)
+}
+
+// optimize se llama de abajo hacia arriba: los hijos ya estan optimizados.
+func optimize(node ast.Node) ast.Node {
+	switch n := node.(type) {
+	case *ast.PrefixExpression:
+		if isConstant(n.Right) {
+			return fold(n, n.Token)
+		}
+	case *ast.InfixExpression:
+		if n.Operator == "??" && isConstant(n.Left) {
+			if _, ok := n.Left.(*ast.NullLiteral); ok {
+				return n.Right
+			}
+			return n.Left
+		}
+		if isConstant(n.Left) && isConstant(n.Right) {
+			return fold(n, n.Token)
+		}
+		return simplifyIdentity(n)
+	case *ast.IfExpression:
+		return pruneIf(n)
+	case *ast.Program:
+		n.Statements = statements(n.Statements, true)
+	case *ast.BlockStatement:
+		n.Statements = statements(n.Statements, true)
+	}
+	return node
+}
+
+// fold evalua e, que solo tiene literales, y lo cambia por el literal del
+// resultado.
+func fold(e ast.Expression, tok token.Token) ast.Expression {
+	result := evaluator.Eval(e, object.NewEnvironment())
+	if literal, ok := objectToLiteral(result, tok); ok {
+		return literal
+	}
+	return e
+}
+
+// simplifyIdentity quita el literal de una identidad. Los tipos del checker
+// no alcanzan para esto, porque BlankSpace unifica con cualquier tipo y
+// BlankSpace + 0 es un error: solo se simplifica si x es un numero por como
+// esta escrito. Sumar 0 ademas pide un entero, porque -0.0 + 0 es 0.0, y
+// los literales son enteros, porque x * 1.0 convierte un entero en flotante.
+func simplifyIdentity(n *ast.InfixExpression) ast.Expression {
+	switch n.Operator {
+	case "+":
+		if isInteger(n.Right, 0) && integer(n.Left) {
+			return n.Left
+		}
+		if isInteger(n.Left, 0) && integer(n.Right) {
+			return n.Right
+		}
+	case "-":
+		if isInteger(n.Right, 0) && numeric(n.Left) {
+			return n.Left
+		}
+	case "*":
+		if isInteger(n.Right, 1) && numeric(n.Left) {
+			return n.Left
+		}
+		if isInteger(n.Left, 1) && numeric(n.Right) {
+			return n.Right
+		}
+	case "/":
+		if isInteger(n.Right, 1) && numeric(n.Left) {
+			return n.Left
+		}
+	}
+	return n
+}
+
+func isInteger(e ast.Expression, value int64) bool {
+	literal, ok := e.(*ast.IntegerLiteral)
+	return ok && literal.Big == nil && literal.Value == value
+}
+
+// numeric dice si e da un numero, o un error, valgan lo que valgan sus
+// variables: los operadores aritmeticos no dan otra cosa, salvo + con dos
+// strings.
+func numeric(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return e.Operator == "-"
+	case *ast.InfixExpression:
+		switch e.Operator {
+		case "-", "*", "/":
+			return true
+		case "+":
+			return numeric(e.Left) || numeric(e.Right)
+		}
+	}
+	return false
+}
+
+// integer es como numeric, pero para expresiones que dan un entero.
+func integer(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.IntegerLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return e.Operator == "-" && integer(e.Right)
+	case *ast.InfixExpression:
+		switch e.Operator {
+		case "+", "-", "*", "/":
+			return integer(e.Left) && integer(e.Right)
+		}
+	}
+	return false
+}
+
+// pruneIf cambia un LoverEra con condicion constante por su rama cuando eso
+// es una expresion: BlankSpace si la rama no existe, o la unica expresion de
+// la rama. Las ramas mas largas las pega statements en el bloque de afuera.
+func pruneIf(n *ast.IfExpression) ast.Expression {
+	if !isConstant(n.Condition) {
+		return n
+	}
+	branch := takenBranch(n)
+	if branch == nil {
+		return &ast.NullLiteral{Token: token.Token{Type: token.NULL, Literal: "BlankSpace",
+			Line: n.Token.Line, Column: n.Token.Column}}
+	}
+	if len(branch.Statements) == 1 {
+		if stmt, ok := branch.Statements[0].(*ast.ExpressionStatement); ok {
+			return stmt.Expression
+		}
+	}
+	return n
+}
+
+func takenBranch(n *ast.IfExpression) *ast.BlockStatement {
+	if isTruthy(n.Condition) {
+		return n.Consequence
+	}
+	return n.Alternative
+}
+
+// statements pega en la lista las ramas de los LoverEra constantes (un
+// bloque no abre un entorno nuevo, asi que da lo mismo) y quita las
+// expresiones constantes cuyo valor no se usa. final dice si el valor de la
+// ultima sentencia es el de la lista.
+func statements(stmts []ast.Statement, final bool) []ast.Statement {
+	result := []ast.Statement{}
+	for i, s := range stmts {
+		last := final && i == len(stmts)-1
+		stmt, ok := s.(*ast.ExpressionStatement)
+		if !ok {
+			result = append(result, s)
+			continue
+		}
+
+		if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok && isConstant(ifExp.Condition) {
+			// Una rama vacia al final dejaria sin valor a la lista.
+			branch := takenBranch(ifExp)
+			if branch != nil && (len(branch.Statements) > 0 || !last) {
+				result = append(result, statements(branch.Statements, last)...)
+				continue
+			}
+		}
+		if isConstant(stmt.Expression) && !last {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func isConstant(e ast.Expression) bool {
+	switch e.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.Boolean, *ast.NullLiteral:
+		return true
+	}
+	return false
+}
+
+// isTruthy sigue la regla del evaluador: solo BlankSpace y BadBlood son
+// falsos.
+func isTruthy(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.NullLiteral:
+		return false
+	case *ast.Boolean:
+		return e.Value
+	}
+	return true
+}
+
+func objectToLiteral(obj object.Object, pos token.Token) (ast.Expression, bool) {
+	tok := token.Token{Line: pos.Line, Column: pos.Column}
+	switch obj := obj.(type) {
+	case *object.Integer:
+		tok.Type, tok.Literal = token.INT, strconv.FormatInt(obj.Value, 10)
+		return &ast.IntegerLiteral{Token: tok, Value: obj.Value}, true
+	case *object.BigInt:
+		tok.Type, tok.Literal = token.INT, obj.Value.String()
+		return &ast.IntegerLiteral{Token: tok, Big: new(big.Int).Set(obj.Value)}, true
+	case *object.Float:
+		if math.IsInf(obj.Value, 0) || math.IsNaN(obj.Value) {
+			return nil, false
+		}
+		tok.Type, tok.Literal = token.FLOAT, strconv.FormatFloat(obj.Value, 'f', -1, 64)
+		if !strings.Contains(tok.Literal, ".") {
+			tok.Literal += ".0"
+		}
+		return &ast.FloatLiteral{Token: tok, Value: obj.Value}, true
+	case *object.String:
+		tok.Type, tok.Literal = token.STRING, obj.Value
+		return &ast.StringLiteral{Token: tok, Value: obj.Value}, true
+	case *object.Bool:
+		if obj.Value {
+			tok.Type, tok.Literal = token.TRUE, "SparksFly"
+		} else {
+			tok.Type, tok.Literal = token.FALSE, "BadBlood"
+		}
+		return &ast.Boolean{Token: tok, Value: obj.Value}, true
+	case *object.Null:
+		tok.Type, tok.Literal = token.NULL, "BlankSpace"
+		return &ast.NullLiteral{Token: tok}, true
+	}
+	return nil, false
+}
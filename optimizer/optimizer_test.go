@@ -0,0 +1,172 @@
+package optimizer
+
+import (
+	"main/ast"
+	"main/evaluator"
+	"main/lexer"
+	"main/object"
+	"main/parser"
+	"main/printer"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores al parsear %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func testOptimize(t *testing.T, input string) string {
+	program := parse(t, input)
+	optimized := Optimize(program)
+	printed, err := printer.Print(optimized)
+	if err != nil {
+		t.Fatalf("Print fallo para %q: %v", input, err)
+	}
+	return strings.TrimSuffix(printed, "\n")
+}
+
+func TestConstantFolding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2 * 60 * 60", "7200;"},
+		{"enchanted x = 1 + 2 * 3; x", "enchanted x = 7;\nx;"},
+		{"-(2 + 3)", "(-5);"},
+		{"x - -(1 + 2)", "x - (-3);"},
+		{"-(-(1 + 2))", "3;"},
+		{"7 / 2", "3;"},
+		{"1.5 * 2", "3.0;"},
+		{"1 / 2.0 + 1", "1.5;"},
+		{`"hola" + " " + "mundo"`, `"hola mundo";`},
+		{`"a" == "a"`, "SparksFly;"},
+		{"!(1 < 2)", "BadBlood;"},
+		{"!BlankSpace", "SparksFly;"},
+		{"BlankSpace == BlankSpace", "SparksFly;"},
+		{"BlankSpace ?? 3", "3;"},
+		{"4 ?? x", "4;"},
+		{"BlankSpace ?? x", "x;"},
+		{"x * (2 + 3)", "x * 5;"},
+		{"len([1 + 1])", "len([2]);"},
+		{"isme(a) { a + 2 * 2 }", "isme(a) {\n\ta + 4;\n};"},
+		// Lo que da error se deja para que falle al ejecutar.
+		{"1 + SparksFly", "1 + SparksFly;"},
+		{`"a" - "b"`, `"a" - "b";`},
+		{"1 / 0", "1 / 0;"},
+		{"1.0 / 0", "1.0 / 0;"},
+	}
+
+	for _, tt := range tests {
+		if got := testOptimize(t, tt.input); got != tt.expected {
+			t.Errorf("%q: esperado=%q, obtenido=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestIdentities(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x * 2 * 1", "x * 2;"},
+		{"1 * -x", "-x;"},
+		{"(x - y) / 1", "x - y;"},
+		{"(x + 1) - 0", "x + 1;"},
+		{"isme(a) { (a * 1.5) * 1 }", "isme(a) {\n\ta * 1.5;\n};"},
+		// Una variable puede valer BlankSpace aunque el checker diga int, y
+		// BlankSpace + 0 es un error.
+		{"enchanted x = 5; x * 1", "enchanted x = 5;\nx * 1;"},
+		{"x + 0", "x + 0;"},
+		{"[1, 2][5] * 1", "[1, 2][5] * 1;"},
+		// Dos strings tambien se suman.
+		{"(x + y) * 1", "(x + y) * 1;"},
+		// Cambiarian el tipo o el signo del cero.
+		{"x * 2 * 1.0", "x * 2 * 1.0;"},
+		{"x * 2 + 0", "x * 2 + 0;"},
+		{"x * 2 - 18446744073709551616", "x * 2 - 18446744073709551616;"},
+		{"x * 2", "x * 2;"},
+	}
+
+	for _, tt := range tests {
+		if got := testOptimize(t, tt.input); got != tt.expected {
+			t.Errorf("%q: esperado=%q, obtenido=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestPruneIf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"LoverEra (1 < 2) { 10 } RepEra { 20 }", "10;"},
+		{"LoverEra (BadBlood) { 10 } RepEra { 20 }", "20;"},
+		{"LoverEra (BadBlood) { 10 }", "BlankSpace;"},
+		{"LoverEra (0) { 10 }", "10;"},
+		{"enchanted y = LoverEra (BlankSpace) { 1 } RepEra { 2 }; y", "enchanted y = 2;\ny;"},
+		{
+			"LoverEra (SparksFly) { enchanted a = 1; SpeakNow(a) }; 3",
+			"enchanted a = 1;\nSpeakNow(a);\n3;",
+		},
+		{"LoverEra (BadBlood) { SpeakNow(1) }; 3", "3;"},
+		{"isme() { LoverEra (SparksFly) { hi 1; }; 2 }", "isme() {\n\thi 1;\n\t2;\n};"},
+		{
+			"LoverEra (SparksFly) { LoverEra (BadBlood) { 1 } RepEra { enchanted b = 2; b } }",
+			"enchanted b = 2;\nb;",
+		},
+		// Una rama vacia al final se deja: el bloque no tiene valor.
+		{"LoverEra (SparksFly) {}", "LoverEra (SparksFly) {};"},
+		{"enchanted x = 1; LoverEra (x) { 1 }", "enchanted x = 1;\nLoverEra (x) {\n\t1;\n};"},
+	}
+
+	for _, tt := range tests {
+		if got := testOptimize(t, tt.input); got != tt.expected {
+			t.Errorf("%q: esperado=%q, obtenido=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestOptimizedProgramsEvaluateTheSame(t *testing.T) {
+	inputs := []string{
+		"2 * 60 * 60",
+		"enchanted x = 5; x * 1 + 0 - 0",
+		"enchanted f = isme(n) { LoverEra (SparksFly) { hi n * 1 + 2 * 3; }; 0 }; f(4)",
+		"LoverEra (BadBlood) { 10 }",
+		"LoverEra (SparksFly) { enchanted a = 1; a + 1 }",
+		"enchanted a = 1; LoverEra (BadBlood) { 2 }",
+		"enchanted a = 1; LoverEra (SparksFly) {}",
+		"1 + SparksFly",
+		`"a" + "b" == "ab"`,
+		"BlankSpace ?? 1 + 1",
+		"[1 + 1, 2 * 2][0 + 1]",
+		"enchanted x = 2.5; x / 1.0 + 0.5",
+		"enchanted f = isme(x) { x + 0 }; f(BlankSpace)",
+		"[1, 2][5] + 0",
+		"enchanted a = [1]; a[5] * 1",
+		"enchanted x = 0.0; -x + 0",
+		"enchanted x = 3; (x * 2) * 1 - 0",
+	}
+
+	for _, input := range inputs {
+		expected := evaluator.Eval(parse(t, input), object.NewEnvironment())
+
+		optimized := Optimize(parse(t, input))
+		got := evaluator.Eval(optimized, object.NewEnvironment())
+
+		if inspect(expected) != inspect(got) {
+			t.Errorf("%q: sin optimizar da %s, optimizado da %s", input, inspect(expected), inspect(got))
+		}
+	}
+}
+
+func inspect(obj object.Object) string {
+	if obj == nil {
+		return "<nil>"
+	}
+	return obj.Inspect()
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"main/ast"
+	"main/lexer"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var jsonCorpus = []string{
+	"enchanted x = 5; enchanted y = 10.12; enchanted kekw = 123456;",
+	"hi 5; hi 10.5; hi isme(x) { x };",
+	"omggg",
+	"5;",
+	"5.5484;",
+	"!5; -15; !-a;",
+	"5 + 5; 5 - 5; 5 * 5; 5 / 5; 5 > 5; 5 < 5; 5 == 5; 5 != 5;",
+	"3.8 + 4 * 5 == 3 * 1.0 + 4 * 5",
+	"SparksFly; BadBlood; SparksFly != BadBlood",
+	"LoverEra (x < y) { x } RepEra { y }",
+	"LoverEra (x) { enchanted a = 1; a }",
+	"isme() {}; isme(x, y) { x + y; }",
+	"add(1, 2 * 3, 4 + 5); add(a + b + c * d / f + g)",
+	`"hello world"; len("abc")`,
+	"[1, 2 * 2, 3 + 3][0]; [];",
+	`{"zeta": 1, "alfa": 2 + 3, 10: "diez", SparksFly: 4}; {}`,
+	`enchanted mate = feat "lib/mate.sp";`,
+	"folklore(x, y) { x + y; }",
+	"enchanted unless = folklore(c, a, b) { quote(LoverEra (!(unquote(c))) { unquote(a) } RepEra { unquote(b) }) };",
+	"BlankSpace; a ?? b == c; a ?? b ?? c + 1",
+	`h?["a"]?["b"] ?? BlankSpace`,
+	"enchanted [a, ...rest] = arr; enchanted [...todo] = arr; enchanted [] = arr;",
+	"enchanted [{name}, b] = arr; enchanted {name, age} = person;",
+	"isme([a, b], {c}) { a }",
+	"9223372036854775808 + 123456789012345678901234567890",
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	inputs := append([]string{}, jsonCorpus...)
+	for _, file := range []string{"../main1.sp", "../main2.sp", "../main3.sp"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("no se pudo leer %s: %v", file, err)
+		}
+		inputs = append(inputs, string(content))
+	}
+
+	for _, input := range inputs {
+		p := New(lexer.New(input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		data, err := ast.MarshalProgram(program)
+		if err != nil {
+			t.Fatalf("MarshalProgram fallo para %q: %v", input, err)
+		}
+		decoded, err := ast.UnmarshalProgram(data)
+		if err != nil {
+			t.Fatalf("UnmarshalProgram fallo para %q: %v", input, err)
+		}
+
+		if decoded.String() != program.String() {
+			t.Errorf("String distinto. esperado=%q, obtenido=%q", program.String(), decoded.String())
+		}
+		if !reflect.DeepEqual(decoded, program) {
+			t.Errorf("el arbol decodificado de %q no es igual al original", input)
+		}
+	}
+}
+
+func TestJSONKeepsPositions(t *testing.T) {
+	program := New(lexer.New("enchanted x = 5;\n  x ?? 2.5")).ParseProgram()
+	data, err := ast.MarshalProgram(program)
+	if err != nil {
+		t.Fatalf("MarshalProgram fallo: %v", err)
+	}
+	decoded, err := ast.UnmarshalProgram(data)
+	if err != nil {
+		t.Fatalf("UnmarshalProgram fallo: %v", err)
+	}
+
+	stmt := decoded.Statements[1].(*ast.ExpressionStatement)
+	infix, ok := stmt.Expression.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.InfixExpression. got=%T", stmt.Expression)
+	}
+	if infix.Token.Position() != "2:5" || infix.Left.(*ast.Variable).Token.Position() != "2:3" {
+		t.Errorf("posiciones erroneas. got=%s y %s",
+			infix.Token.Position(), infix.Left.(*ast.Variable).Token.Position())
+	}
+}
+
+func TestJSONUnknownKind(t *testing.T) {
+	_, err := ast.UnmarshalProgram([]byte(`{"kind": "Program", "statements": [{"kind": "Nada"}]}`))
+	if err == nil {
+		t.Fatalf("se esperaba un error para un tipo de nodo desconocido")
+	}
+}
+
+func TestJSONInvalidChildren(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"kind": "Program", "statements": [{"kind": "NullLiteral"}]}`,
+			"Program: se esperaba una sentencia, se obtuvo: NullLiteral"},
+		{`{"kind": "Program", "statements": [{"kind": "ExpressionStatement",
+			"expression": {"kind": "ReturnStatement", "returnValue": {"kind": "NullLiteral"}}}]}`,
+			"ExpressionStatement: se esperaba una expresion, se obtuvo: ReturnStatement"},
+		{`{"kind": "Program", "statements": [{"kind": "LetStatement",
+			"name": {"kind": "StringLiteral", "value": "x"}, "value": {"kind": "NullLiteral"}}]}`,
+			"LetStatement: se esperaba un Variable, se obtuvo: StringLiteral"},
+		{`{"kind": "Program", "statements": [{"kind": "ExpressionStatement",
+			"expression": {"kind": "FunctionLiteral", "body": {"kind": "NullLiteral"}}}]}`,
+			"FunctionLiteral: se esperaba un BlockStatement, se obtuvo: NullLiteral"},
+		{`{"kind": "Program", "statements": [{"kind": "ExpressionStatement",
+			"expression": {"kind": "InfixExpression", "operator": "+", "left": {"kind": "NullLiteral"}}}]}`,
+			"InfixExpression: falta right"},
+		{`{"kind": "Program", "statements": [{"kind": "ExpressionStatement",
+			"expression": {"kind": "IfExpression", "condition": {"kind": "NullLiteral"}, "consequence": null}}]}`,
+			"IfExpression: falta consequence"},
+		{`{"kind": "Program", "statements": [{"kind": "LetStatement",
+			"name": {"kind": "Variable", "value": "x"}}]}`,
+			"LetStatement: falta value"},
+		{`{"kind": "Program", "statements": [null]}`, "Program: falta statements"},
+	}
+
+	for _, tt := range tests {
+		_, err := ast.UnmarshalProgram([]byte(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("error erroneo. esperado=%q, obtenido=%v", tt.expected, err)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package parser
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"main/ast"
 	"main/lexer"
 	"main/token"
+	"math/big"
 	"os"
 	"strconv"
 )
@@ -14,6 +16,7 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	NULLISH     // ??
 	EQUALS      // ==
 	LESSGREATER // > o <
 	SUM         // +
@@ -34,6 +37,9 @@ var precedences = map[token.TokenType]int{
 	token.TIMES:    PRODUCT,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX,
+
+	token.NULLISH:      NULLISH,
+	token.OPT_LBRACKET: INDEX,
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -87,15 +93,20 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.OPT_LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.NULLISH, p.parseInfixExpression)
 
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
 
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.ID, p.parseIdentifier)
 
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.IMPORT, p.parseImportExpression)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 
@@ -138,6 +149,19 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
+	lit.Parameters = p.parsePatternList()
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	lit.Body = p.parseBlockStatement()
+	return lit
+}
+
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
 	lit.Parameters = p.parseFunctionParameters()
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -218,6 +242,10 @@ func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Variable{Token: p.curToken, Value: p.curToken.Literal}
 }
@@ -334,6 +362,14 @@ func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// Un literal que no cabe en un int64 es un BigInt, igual que el
+		// resultado de una operacion que se desborda.
+		if n, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			lit.Big = n
+			return lit
+		}
+	}
 	if err != nil {
 		msg := fmt.Sprintf("No se pudo convertir %q a un entero", p.curToken.Literal)
 		p.errors = append(p.errors, msg)
@@ -370,10 +406,18 @@ func (p *Parser) parseStatement() ast.Statement {
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
-	if !p.expectPeek(token.ID) {
-		return nil
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.ID) {
+			return nil
+		}
+		stmt.Name = &ast.Variable{Token: p.curToken, Value: p.curToken.Literal}
 	}
-	stmt.Name = &ast.Variable{Token: p.curToken, Value: p.curToken.Literal}
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -435,6 +479,7 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	exp.Optional = p.curTokenIs(token.OPT_LBRACKET)
 	p.nextToken()
 	exp.Index = p.parseExpression(LOWEST)
 	if !p.expectPeek(token.RBRACKET) {
@@ -446,7 +491,7 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 // --------------------------Hash--------------------------------------
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	hash.Pairs = []ast.HashPair{}
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
 		key := p.parseExpression(LOWEST)
@@ -455,7 +500,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		}
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
 		}
@@ -466,6 +511,96 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+// --------------------------Destructuring--------------------------------------
+
+// parsePatternList lee los parametros de una funcion, que pueden ser patrones.
+func (p *Parser) parsePatternList() []ast.Pattern {
+	patterns := []ast.Pattern{}
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return patterns
+	}
+	p.nextToken()
+	patterns = append(patterns, p.parsePattern())
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		patterns = append(patterns, p.parsePattern())
+	}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return patterns
+}
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.ID:
+		return &ast.Variable{Token: p.curToken, Value: p.curToken.Literal}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	default:
+		msg := fmt.Sprintf("Se esperaba un nombre o un patron, se obtuvo: %s", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+}
+
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.ID) {
+				return nil
+			}
+			pattern.Rest = &ast.Variable{Token: p.curToken, Value: p.curToken.Literal}
+			break
+		}
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return pattern
+}
+
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.ID) {
+			return nil
+		}
+		pattern.Keys = append(pattern.Keys, &ast.Variable{Token: p.curToken, Value: p.curToken.Literal})
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return pattern
+}
+
+// --------------------------Modulos--------------------------------------
+func (p *Parser) parseImportExpression() ast.Expression {
+	exp := &ast.ImportExpression{Token: p.curToken}
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+	exp.Path = p.curToken.Literal
+	return exp
+}
+
 // ------------REPL----------------------------------------------------
 
 const PROMPT = "Speak Noooowww >> "
@@ -170,6 +170,25 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntegerLiteral(t *testing.T) {
+	input := "9223372036854775808;"
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.IntegerLiteral. got=%T", stmt.Expression)
+	}
+	if literal.Big == nil || literal.Big.String() != "9223372036854775808" {
+		t.Errorf("literal.Big not 9223372036854775808. got=%v", literal.Big)
+	}
+	if literal.Value != 0 {
+		t.Errorf("literal.Value should be 0 for a big literal. got=%d", literal.Value)
+	}
+}
+
 func TestFloatExpressions(t *testing.T) {
 	input := "5.5484;"
 	l := lexer.New(input)
@@ -415,6 +434,18 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"3.2 + 4.2 * 5.5 == 3.1 * 1.2 + 4.0 * 5.5",
 			"((3.2 + (4.2 * 5.5)) == ((3.1 * 1.2) + (4.0 * 5.5)))",
 		},
+		{
+			"a ?? b == c",
+			"(a ?? (b == c))",
+		},
+		{
+			"a ?? b ?? c + 1",
+			"((a ?? b) ?? (c + 1))",
+		},
+		{
+			`h?["a"]?["b"] ?? BlankSpace`,
+			"(((h?[a])?[b]) ?? BlankSpace)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -428,3 +459,156 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestImportExpression(t *testing.T) {
+	input := `enchanted mate = feat "lib/mate.sp";`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.LetStatement. got=%T",
+			program.Statements[0])
+	}
+	imp, ok := stmt.Value.(*ast.ImportExpression)
+	if !ok {
+		t.Fatalf("stmt.Value is not ast.ImportExpression. got=%T", stmt.Value)
+	}
+	if imp.Path != "lib/mate.sp" {
+		t.Errorf("imp.Path not %q. got=%q", "lib/mate.sp", imp.Path)
+	}
+	if program.String() != `enchanted mate = feat "lib/mate.sp";` {
+		t.Errorf("program.String() wrong. got=%q", program.String())
+	}
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	input := `folklore(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T",
+			stmt.Expression)
+	}
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro literal parameters wrong. want 2, got=%d\n",
+			len(macro.Parameters))
+	}
+	if macro.Parameters[0].Value != "x" || macro.Parameters[1].Value != "y" {
+		t.Fatalf("macro parameters wrong. got=%s, %s",
+			macro.Parameters[0], macro.Parameters[1])
+	}
+	if macro.Body.String() != "(x + y)" {
+		t.Errorf("body is not %q. got=%q", "(x + y)", macro.Body.String())
+	}
+}
+
+func TestDestructuringPatterns(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enchanted [a, b] = arr;", "enchanted [a, b] = arr;"},
+		{"enchanted [a, ...rest] = arr;", "enchanted [a, ...rest] = arr;"},
+		{"enchanted [...todo] = arr;", "enchanted [...todo] = arr;"},
+		{"enchanted [a, [b, c]] = arr;", "enchanted [a, [b, c]] = arr;"},
+		{"enchanted [] = arr;", "enchanted [] = arr;"},
+		{"enchanted {name, age} = person;", "enchanted {name, age} = person;"},
+		{"enchanted [{name}, b] = arr;", "enchanted [{name}, b] = arr;"},
+		{"isme([a, b], {c}) { a }", "isme([a, b], {c}) a"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	l := lexer.New("enchanted [a, ...rest] = arr;")
+	p := New(l)
+	program := p.ParseProgram()
+	stmt := program.Statements[0].(*ast.LetStatement)
+	if stmt.Name != nil {
+		t.Errorf("stmt.Name should be nil for a pattern. got=%s", stmt.Name)
+	}
+	pattern, ok := stmt.Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("stmt.Pattern is not ast.ArrayPattern. got=%T", stmt.Pattern)
+	}
+	if len(pattern.Elements) != 1 || pattern.Rest == nil || pattern.Rest.Value != "rest" {
+		t.Errorf("pattern wrong. got=%s", pattern.String())
+	}
+}
+
+func TestDestructuringPatternErrors(t *testing.T) {
+	tests := []string{
+		"enchanted [a, ...rest, b] = arr;",
+		"enchanted [1] = arr;",
+		"enchanted {a: b} = h;",
+		"enchanted [a b] = arr;",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestParsingHashLiteralsKeepOrder(t *testing.T) {
+	input := `{"zeta": 1, "alfa": 2 + 3, 10: "diez", SparksFly: 4}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expectedKeys := []string{"zeta", "alfa", "10", "SparksFly"}
+	if len(hash.Pairs) != len(expectedKeys) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+	for i, key := range expectedKeys {
+		if hash.Pairs[i].Key.String() != key {
+			t.Errorf("hash.Pairs[%d].Key wrong. want=%q, got=%q", i, key, hash.Pairs[i].Key.String())
+		}
+	}
+
+	expected := "{zeta:1, alfa:(2 + 3), 10:diez, SparksFly:4}"
+	for i := 0; i < 20; i++ {
+		if hash.String() != expected {
+			t.Fatalf("hash.String() wrong. want=%q, got=%q", expected, hash.String())
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"main/lexer"
+	"main/parser"
+	"main/token"
+	"strings"
+)
+
+// Format parsea src y lo devuelve en el formato canonico de Print, pero
+// conservando los comentarios y las lineas en blanco entre sentencias.
+func Format(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	pr := &printer{layout: newLayout(string(src))}
+	pr.node(program)
+	if pr.err != nil {
+		return nil, pr.err
+	}
+	return pr.out.Bytes(), nil
+}
+
+// IsFormatted dice si src ya esta en el formato que produce Format.
+func IsFormatted(src []byte) (bool, error) {
+	formatted, err := Format(src)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(src, formatted), nil
+}
+
+// layout guarda lo que el AST no tiene del archivo original: los tokens con
+// sus posiciones, los comentarios y las lineas en blanco. Todos sus metodos
+// aceptan un layout nil, que es el caso de Print.
+type layout struct {
+	tokens   []token.Token
+	indexes  map[[2]int]int // linea y columna -> indice en tokens
+	closers  map[int]int    // indice de ( [ { ?[ -> indice de su cierre
+	comments []lexer.Comment
+	next     int // primer comentario que falta imprimir
+	blank    map[int]bool
+}
+
+func newLayout(src string) *layout {
+	lay := &layout{
+		indexes: map[[2]int]int{},
+		closers: map[int]int{},
+		blank:   map[int]bool{},
+	}
+
+	l := lexer.New(src)
+	var open []int
+	for {
+		tok := l.NextToken()
+		i := len(lay.tokens)
+		lay.tokens = append(lay.tokens, tok)
+		lay.indexes[[2]int{tok.Line, tok.Column}] = i
+
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE, token.OPT_LBRACKET:
+			open = append(open, i)
+		case token.RPAREN, token.RBRACKET, token.RBRACE:
+			if len(open) > 0 {
+				lay.closers[open[len(open)-1]] = i
+				open = open[:len(open)-1]
+			}
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+	lay.comments = l.Comments()
+
+	for i, line := range strings.Split(src, "\n") {
+		if strings.TrimSpace(line) == "" {
+			lay.blank[i+1] = true
+		}
+	}
+	return lay
+}
+
+// end es el indice del EOF.
+func (lay *layout) end() int {
+	if lay == nil {
+		return 0
+	}
+	return len(lay.tokens) - 1
+}
+
+func (lay *layout) index(tok token.Token) int {
+	if lay == nil {
+		return 0
+	}
+	return lay.indexes[[2]int{tok.Line, tok.Column}]
+}
+
+// closer es el indice del token que cierra al que abre con tok.
+func (lay *layout) closer(tok token.Token) int {
+	if lay == nil {
+		return 0
+	}
+	return lay.closers[lay.index(tok)]
+}
+
+func (lay *layout) line(index int) int {
+	if lay == nil || index < 0 || index >= len(lay.tokens) {
+		return 0
+	}
+	return lay.tokens[index].Line
+}
+
+func (lay *layout) blankBefore(line int) bool {
+	return lay != nil && lay.blank[line-1]
+}
+
+func (lay *layout) before(c lexer.Comment, index int) bool {
+	tok := lay.tokens[index]
+	return c.Line < tok.Line || c.Line == tok.Line && c.Column < tok.Column
+}
+
+func (lay *layout) hasCommentsBefore(index int) bool {
+	return lay != nil && lay.next < len(lay.comments) && lay.before(lay.comments[lay.next], index)
+}
+
+// commentsBefore consume los comentarios pendientes que estan antes del token
+// index.
+func (lay *layout) commentsBefore(index int) []lexer.Comment {
+	if lay == nil {
+		return nil
+	}
+	start := lay.next
+	for lay.hasCommentsBefore(index) {
+		lay.next++
+	}
+	return lay.comments[start:lay.next]
+}
+
+// commentsThrough consume los comentarios pendientes hasta la linea line.
+func (lay *layout) commentsThrough(line int) []lexer.Comment {
+	if lay == nil {
+		return nil
+	}
+	start := lay.next
+	for lay.next < len(lay.comments) && lay.comments[lay.next].Line <= line {
+		lay.next++
+	}
+	return lay.comments[start:lay.next]
+}
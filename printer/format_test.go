@@ -0,0 +1,85 @@
+package printer
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"enchanted   x=5\nenchanted y =x*2",
+			"enchanted x = 5;\nenchanted y = x * 2;\n",
+		},
+		{
+			"enchanted x = 1;\n\n\n\nenchanted y = 2;\n",
+			"enchanted x = 1;\n\nenchanted y = 2;\n",
+		},
+		{
+			"// inicio\n\n// suma\nenchanted x = 1 + 2; // tres\nx",
+			"// inicio\n\n// suma\nenchanted x = 1 + 2; // tres\nx;\n",
+		},
+		{
+			"enchanted f = isme(x) {\n  // dentro\n    enchanted y = x;\n\n  y // fin\n  // antes de cerrar\n}",
+			"enchanted f = isme(x) {\n\t// dentro\n\tenchanted y = x;\n\n\ty; // fin\n\t// antes de cerrar\n};\n",
+		},
+		{
+			"LoverEra (x) {\n// vacio\n} RepEra { 2 }",
+			"LoverEra (x) {\n\t// vacio\n} RepEra {\n\t2;\n};\n",
+		},
+		{"// solo un comentario\n", "// solo un comentario\n"},
+		{"\n\n", ""},
+	}
+
+	for _, tt := range tests {
+		formatted, err := Format([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("Format fallo para %q: %v", tt.input, err)
+		}
+		if string(formatted) != tt.expected {
+			t.Errorf("formato erroneo para %q.\nesperado=%q\nobtenido=%q", tt.input, tt.expected, formatted)
+		}
+
+		again, err := Format(formatted)
+		if err != nil {
+			t.Fatalf("Format fallo para %q: %v", formatted, err)
+		}
+		if string(again) != string(formatted) {
+			t.Errorf("el formato no es estable.\nprimera=%q\nsegunda=%q", formatted, again)
+		}
+	}
+}
+
+func TestIsFormatted(t *testing.T) {
+	ok, err := IsFormatted([]byte("enchanted x = 5;\n"))
+	if err != nil || !ok {
+		t.Errorf("se esperaba que estuviera formateado. ok=%t err=%v", ok, err)
+	}
+	ok, err = IsFormatted([]byte("enchanted x=5"))
+	if err != nil || ok {
+		t.Errorf("se esperaba que no estuviera formateado. ok=%t err=%v", ok, err)
+	}
+	if _, err := IsFormatted([]byte("enchanted = 5")); err == nil {
+		t.Errorf("se esperaba un error de parseo")
+	}
+}
+
+func TestFormatRejectsCommentsInsideExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enchanted a = [1,\n  // en medio\n  2]; // final\nhi a", "2:3: no se puede formatear un comentario dentro de una expresion"},
+		{"f(1, // uno\n2)", "1:6: no se puede formatear un comentario dentro de una expresion"},
+		{"isme(x) {\n\thi x +\n\t// dos\n\t2\n}", "3:2: no se puede formatear un comentario dentro de una expresion"},
+	}
+
+	for _, tt := range tests {
+		_, err := Format([]byte(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("error erroneo para %q. esperado=%q, obtenido=%v", tt.input, tt.expected, err)
+		}
+	}
+}
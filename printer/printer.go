@@ -0,0 +1,387 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"main/ast"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Precedencias de los operadores infijos, igual que en el parser. Sirven para
+// poner solo los parentesis que hacen falta para volver al mismo arbol.
+const (
+	_ int = iota
+	lowest
+	nullish
+	equals
+	lessGreater
+	sum
+	product
+	prefix
+)
+
+var precedences = map[string]int{
+	"??": nullish,
+	"==": equals,
+	"!=": equals,
+	"<":  lessGreater,
+	">":  lessGreater,
+	"+":  sum,
+	"-":  sum,
+	"*":  product,
+	"/":  product,
+}
+
+// Print devuelve el codigo fuente canonico de node. Al volver a parsearlo se
+// obtiene el mismo arbol, salvo por las posiciones de los tokens.
+func Print(node ast.Node) (string, error) {
+	var out bytes.Buffer
+	if err := Fprint(&out, node); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func Fprint(w io.Writer, node ast.Node) error {
+	p := &printer{}
+	p.node(node)
+	if p.err != nil {
+		return p.err
+	}
+	_, err := w.Write(p.out.Bytes())
+	return err
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+	err    error
+
+	// layout solo existe al formatear un archivo; trae los comentarios y las
+	// lineas en blanco del codigo original.
+	layout *layout
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+// startLine empieza una linea nueva, salvo al inicio de la salida.
+func (p *printer) startLine() {
+	if p.out.Len() > 0 {
+		p.newline()
+	}
+}
+
+func (p *printer) newline() {
+	p.write("\n")
+	p.write(strings.Repeat("\t", p.indent))
+}
+
+func (p *printer) fail(format string, a ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf(format, a...)
+	}
+}
+
+func (p *printer) node(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Program:
+		p.statementList(n.Statements, p.layout.end())
+		if p.out.Len() > 0 {
+			p.write("\n")
+		}
+	case ast.Statement:
+		p.statement(n)
+	case ast.Expression:
+		p.expression(n, lowest)
+	default:
+		p.fail("no se puede imprimir el nodo %T", node)
+	}
+}
+
+// ---------------------------Statements--------------------------------
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		p.write("enchanted ")
+		p.pattern(s.Target())
+		p.write(" = ")
+		p.expression(s.Value, lowest)
+		p.write(";")
+	case *ast.ReturnStatement:
+		p.write("hi ")
+		p.expression(s.ReturnValue, lowest)
+		p.write(";")
+	case *ast.ExpressionStatement:
+		p.expression(s.Expression, lowest)
+		p.write(";")
+	case *ast.BlockStatement:
+		p.block(s)
+	default:
+		p.fail("no se puede imprimir la sentencia %T", stmt)
+	}
+}
+
+func (p *printer) block(b *ast.BlockStatement) {
+	closer := p.layout.closer(b.Token)
+	if len(b.Statements) == 0 && !p.layout.hasCommentsBefore(closer) {
+		p.write("{}")
+		return
+	}
+	p.write("{")
+	p.indent++
+	p.statementList(b.Statements, closer)
+	p.indent--
+	p.newline()
+	p.write("}")
+}
+
+// statementList imprime cada sentencia en su propia linea. Con layout,
+// intercala los comentarios que aparecen antes de cada una y antes de closer,
+// el token que cierra la lista.
+func (p *printer) statementList(stmts []ast.Statement, closer int) {
+	first := true
+	for i, s := range stmts {
+		start := p.layout.index(ast.TokenOf(s))
+		first = p.leadingComments(start, first)
+
+		p.separate(p.layout.line(start), first)
+		p.statement(s)
+		first = false
+
+		next := closer
+		if i+1 < len(stmts) {
+			next = p.layout.index(ast.TokenOf(stmts[i+1]))
+		}
+		p.trailingComments(p.layout.line(next - 1))
+	}
+	p.leadingComments(closer, first)
+}
+
+// separate empieza la linea de un elemento que en el original estaba en line,
+// dejando una linea en blanco si el original la tenia.
+func (p *printer) separate(line int, first bool) {
+	if !first && p.layout.blankBefore(line) {
+		p.write("\n")
+	}
+	p.startLine()
+}
+
+// leadingComments imprime los comentarios pendientes que van antes del token
+// index, cada uno en su linea.
+func (p *printer) leadingComments(index int, first bool) bool {
+	for _, c := range p.layout.commentsBefore(index) {
+		p.separate(c.Line, first)
+		p.write(c.Text)
+		first = false
+	}
+	return first
+}
+
+// trailingComments imprime el comentario que sigue a una sentencia que
+// termina en la linea end. Los de lineas anteriores quedaron dentro de una
+// expresion, donde Print no tiene lugar para ellos: moverlos cambiaria lo que
+// comentan, asi que se rechaza el archivo.
+func (p *printer) trailingComments(end int) {
+	for _, c := range p.layout.commentsThrough(end) {
+		if c.Line != end {
+			p.fail("%d:%d: no se puede formatear un comentario dentro de una expresion", c.Line, c.Column)
+			return
+		}
+		p.write(" " + c.Text)
+	}
+}
+
+// ---------------------------Expressions--------------------------------
+
+// expression imprime e entre parentesis si su precedencia es menor a la que
+// pide el contexto.
+func (p *printer) expression(e ast.Expression, context int) {
+	if precedence := precedenceOf(e); precedence < context {
+		p.write("(")
+		defer p.write(")")
+	}
+
+	switch n := e.(type) {
+	case *ast.Variable:
+		p.write(n.Value)
+	case *ast.IntegerLiteral:
+		if n.Big != nil {
+			p.number(n.Big.String())
+		} else {
+			p.number(strconv.FormatInt(n.Value, 10))
+		}
+	case *ast.FloatLiteral:
+		if math.IsInf(n.Value, 0) || math.IsNaN(n.Value) {
+			p.fail("el flotante %v no se puede escribir: el lenguaje no tiene Inf ni NaN", n.Value)
+		}
+		p.number(formatFloat(n.Value))
+	case *ast.StringLiteral:
+		if strings.Contains(n.Value, `"`) {
+			p.fail("el string %q no se puede escribir: contiene comillas", n.Value)
+		}
+		p.write(`"` + n.Value + `"`)
+	case *ast.Boolean:
+		if n.Value {
+			p.write("SparksFly")
+		} else {
+			p.write("BadBlood")
+		}
+	case *ast.NullLiteral:
+		p.write("BlankSpace")
+
+	case *ast.PrefixExpression:
+		p.write(n.Operator)
+		p.expression(n.Right, prefix)
+	case *ast.InfixExpression:
+		precedence := precedences[n.Operator]
+		p.expression(n.Left, precedence)
+		p.write(" " + n.Operator + " ")
+		// Todos los operadores asocian a la izquierda.
+		p.expression(n.Right, precedence+1)
+
+	case *ast.IfExpression:
+		p.write("LoverEra (")
+		p.expression(n.Condition, lowest)
+		p.write(") ")
+		p.block(n.Consequence)
+		if n.Alternative != nil {
+			p.write(" RepEra ")
+			p.block(n.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		p.write("isme(")
+		for i, param := range n.Parameters {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.pattern(param)
+		}
+		p.write(") ")
+		p.block(n.Body)
+	case *ast.MacroLiteral:
+		p.write("folklore(")
+		for i, param := range n.Parameters {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.write(param.Value)
+		}
+		p.write(") ")
+		p.block(n.Body)
+	case *ast.CallExpression:
+		p.expression(n.Function, prefix+1)
+		p.write("(")
+		p.expressionList(n.Arguments)
+		p.write(")")
+	case *ast.IndexExpression:
+		p.expression(n.Left, prefix+1)
+		if n.Optional {
+			p.write("?[")
+		} else {
+			p.write("[")
+		}
+		p.expression(n.Index, lowest)
+		p.write("]")
+	case *ast.ArrayLiteral:
+		p.write("[")
+		p.expressionList(n.Elements)
+		p.write("]")
+	case *ast.HashLiteral:
+		p.write("{")
+		for i, pair := range n.Pairs {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(pair.Key, lowest)
+			p.write(": ")
+			p.expression(pair.Value, lowest)
+		}
+		p.write("}")
+	case *ast.ImportExpression:
+		p.write(`feat "` + n.Path + `"`)
+
+	default:
+		p.fail("no se puede imprimir la expresion %T", e)
+	}
+}
+
+func (p *printer) expressionList(list []ast.Expression) {
+	for i, e := range list {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.expression(e, lowest)
+	}
+}
+
+func (p *printer) pattern(pattern ast.Pattern) {
+	switch n := pattern.(type) {
+	case *ast.Variable:
+		p.write(n.Value)
+	case *ast.ArrayPattern:
+		p.write("[")
+		for i, e := range n.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.pattern(e)
+		}
+		if n.Rest != nil {
+			if len(n.Elements) > 0 {
+				p.write(", ")
+			}
+			p.write("..." + n.Rest.Value)
+		}
+		p.write("]")
+	case *ast.HashPattern:
+		p.write("{")
+		for i, k := range n.Keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.write(k.Value)
+		}
+		p.write("}")
+	default:
+		p.fail("no se puede imprimir el patron %T", pattern)
+	}
+}
+
+// precedenceOf es la precedencia de e como operando: los operadores prefijos
+// e infijos tienen la suya y todo lo demas nunca necesita parentesis.
+func precedenceOf(e ast.Expression) int {
+	switch n := e.(type) {
+	case *ast.InfixExpression:
+		return precedences[n.Operator]
+	case *ast.PrefixExpression:
+		return prefix
+	default:
+		return prefix + 1
+	}
+}
+
+// number escribe un literal. El parser no tiene literales negativos: lee -3
+// como el prefijo - sobre 3, asi que uno negativo, como los que arma el
+// optimizer, va entre parentesis para que se lea igual en cualquier lugar.
+func (p *printer) number(literal string) {
+	if strings.HasPrefix(literal, "-") {
+		p.write("(" + literal + ")")
+		return
+	}
+	p.write(literal)
+}
+
+// formatFloat siempre deja un punto decimal, si no el lexer leeria un entero.
+func formatFloat(value float64) string {
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package printer
+
+import (
+	"main/ast"
+	"main/lexer"
+	"main/parser"
+	"main/token"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var corpus = []string{
+	"enchanted x = 5; enchanted y = 10.12; enchanted kekw = 123456;",
+	"hi 5; hi 10.50; hi isme(x) { x };",
+	"5.5484; 1.0; 0.25; 9223372036854775808",
+	"!5; -15; !-a; --a; -(-a)",
+	"5 + 5; 5 - 5; 5 * 5; 5 / 5; 5 > 5; 5 < 5; 5 == 5; 5 != 5;",
+	"a - (b - c); (a - b) - c; a * (b + c); -(a + b) * c",
+	"3.8 + 4 * 5 == 3 * 1.0 + 4 * 5",
+	"(a < b) == (c > d); a == (b == c)",
+	"SparksFly; BadBlood; SparksFly != BadBlood",
+	"LoverEra (x < y) { x } RepEra { y }",
+	"LoverEra (x) { enchanted a = 1; LoverEra (a) { hi a; } } + 1",
+	"isme() {}; isme(x, y) { x + y; }",
+	"isme(x) { x }(5); (isme(x) { x })(5); (-f)(1); -f(1)",
+	"add(1, 2 * 3, 4 + 5); add(a + b + c * d / f + g)",
+	`"hello world"; len("abc"); ""`,
+	"[1, 2 * 2, 3 + 3][0]; []; (a + b)[1]; [[1]][0][0]",
+	`{"zeta": 1, "alfa": 2 + 3, 10: "diez", SparksFly: 4}; {}`,
+	`enchanted mate = feat "lib/mate.sp"; mate["suma"](1, 2)`,
+	"folklore(x, y) { x + y; }",
+	"enchanted unless = folklore(c, a, b) { quote(LoverEra (!(unquote(c))) { unquote(a) } RepEra { unquote(b) }) };",
+	"BlankSpace; a ?? b == c; a ?? b ?? c + 1; a ?? (b ?? c); (a ?? b) == c",
+	`h?["a"]?["b"] ?? BlankSpace; (h ?? g)?["a"]`,
+	"enchanted [a, ...rest] = arr; enchanted [...todo] = arr; enchanted [] = arr;",
+	"enchanted [{name}, [b, c]] = arr; enchanted {name, age} = person;",
+	"isme([a, b], {c}, d) { a }",
+}
+
+// stripTokens borra los tokens de todo el arbol: el printer decide sus
+// propios parentesis y posiciones, asi que solo se compara la estructura.
+func stripTokens(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			stripTokens(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			stripTokens(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(token.Token{}) {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			stripTokens(v.Field(i))
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores al parsear %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func TestPrintRoundTrip(t *testing.T) {
+	inputs := append([]string{}, corpus...)
+	for _, file := range []string{"../main1.sp", "../main2.sp", "../main3.sp"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("no se pudo leer %s: %v", file, err)
+		}
+		inputs = append(inputs, string(content))
+	}
+
+	for _, input := range inputs {
+		original := parse(t, input)
+		printed, err := Print(original)
+		if err != nil {
+			t.Fatalf("Print fallo para %q: %v", input, err)
+		}
+		reparsed := parse(t, printed)
+
+		stripTokens(reflect.ValueOf(original))
+		stripTokens(reflect.ValueOf(reparsed))
+		if !reflect.DeepEqual(original, reparsed) {
+			t.Errorf("el arbol cambio al imprimir %q:\n%s", input, printed)
+		}
+
+		again, err := Print(reparsed)
+		if err != nil {
+			t.Fatalf("Print fallo para %q: %v", printed, err)
+		}
+		if again != printed {
+			t.Errorf("la salida no es estable.\nprimera:\n%s\nsegunda:\n%s", printed, again)
+		}
+	}
+}
+
+func TestPrintCanonicalForm(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enchanted   x=5", "enchanted x = 5;\n"},
+		{"((a + b)) * c", "(a + b) * c;\n"},
+		{"a + (b * c)", "a + b * c;\n"},
+		{"2.50", "2.5;\n"},
+		{"LoverEra (x) { 1 } RepEra { }", "LoverEra (x) {\n\t1;\n} RepEra {};\n"},
+		{
+			"enchanted f = isme(x) { LoverEra (x) { hi 1; } 2 }",
+			"enchanted f = isme(x) {\n\tLoverEra (x) {\n\t\thi 1;\n\t};\n\t2;\n};\n",
+		},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		printed, err := Print(parse(t, tt.input))
+		if err != nil {
+			t.Fatalf("Print fallo para %q: %v", tt.input, err)
+		}
+		if printed != tt.expected {
+			t.Errorf("salida erronea para %q.\nesperado=%q\nobtenido=%q", tt.input, tt.expected, printed)
+		}
+	}
+}
+
+func TestPrintBuiltTrees(t *testing.T) {
+	a := &ast.Variable{Value: "a"}
+	b := &ast.Variable{Value: "b"}
+	tests := []struct {
+		node     ast.Node
+		expected string
+	}{
+		{
+			&ast.InfixExpression{Operator: "*", Left: &ast.InfixExpression{Operator: "+", Left: a, Right: b}, Right: a},
+			"(a + b) * a",
+		},
+		{
+			&ast.InfixExpression{Operator: "-", Left: a, Right: &ast.InfixExpression{Operator: "-", Left: a, Right: b}},
+			"a - (a - b)",
+		},
+		{&ast.PrefixExpression{Operator: "-", Right: &ast.IntegerLiteral{Value: -3}}, "-(-3)"},
+		{&ast.InfixExpression{Operator: "-", Left: a, Right: &ast.IntegerLiteral{Value: -3}}, "a - (-3)"},
+		{&ast.IndexExpression{Left: &ast.IntegerLiteral{Value: -3}, Index: a}, "(-3)[a]"},
+		{&ast.FloatLiteral{Value: -2.5}, "(-2.5)"},
+		{&ast.FloatLiteral{Value: math.Copysign(0, -1)}, "(-0.0)"},
+		{&ast.IntegerLiteral{Big: big.NewInt(0).Lsh(big.NewInt(-1), 64)}, "(-18446744073709551616)"},
+		{&ast.FloatLiteral{Value: 3}, "3.0"},
+		{&ast.Boolean{Value: true}, "SparksFly"},
+	}
+
+	for _, tt := range tests {
+		printed, err := Print(tt.node)
+		if err != nil {
+			t.Fatalf("Print fallo: %v", err)
+		}
+		if printed != tt.expected {
+			t.Errorf("esperado=%q, obtenido=%q", tt.expected, printed)
+		}
+	}
+
+	if _, err := Print(&ast.StringLiteral{Value: `di "hola"`}); err == nil {
+		t.Errorf("se esperaba un error para un string con comillas")
+	}
+	for _, value := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		if _, err := Print(&ast.FloatLiteral{Value: value}); err == nil {
+			t.Errorf("se esperaba un error para el flotante %v", value)
+		}
+	}
+}
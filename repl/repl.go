@@ -1,14 +1,23 @@
 package repl
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"log"
+	"main/ast"
 	"main/compiler"
 	"main/evaluator"
 	"main/lexer"
+	"main/lint"
 	"main/object"
+	"main/optimizer"
 	"main/parser"
+	"main/printer"
+	"main/resolver"
+	"main/typechecker"
 	"os"
+	"strings"
 )
 
 func writeToFile(filePath string, content string) error {
@@ -26,6 +35,8 @@ func writeToFile(filePath string, content string) error {
 
 func Start(filePath string, out io.Writer) {
 	env := object.NewEnvironment()
+	env.SetFile(filePath)
+	env.Runtime().Stdout = out
 
 	// Read the entire file content
 	fileContent, err := os.ReadFile(filePath)
@@ -59,14 +70,160 @@ func Start(filePath string, out io.Writer) {
 		return
 	}
 
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, macroErrors := evaluator.ExpandMacros(program, macroEnv)
+	if len(macroErrors) != 0 {
+		printParserErrors(out, macroErrors)
+		return
+	}
+	program = expanded.(*ast.Program)
+
+	r := resolver.New()
+	r.Predeclare(evaluator.BuiltinNames()...)
+	r.Resolve(program)
+	if len(r.Errors()) != 0 {
+		printParserErrors(out, r.Errors())
+		return
+	}
+
+	checker := typechecker.New()
+	checker.Predeclare(evaluator.Predeclared())
+	checker.Check(program)
+	if len(checker.Errors()) != 0 {
+		printParserErrors(out, checker.Errors())
+		return
+	}
+
+	program = optimizer.Optimize(program).(*ast.Program)
+
 	evaluated := evaluator.Eval(program, env)
 
-	if evaluated != nil {
+	if errObj, ok := evaluated.(*object.Error); ok {
+		io.WriteString(out, errObj.Traceback())
+		io.WriteString(out, "\n")
+	} else if evaluated != nil {
 		io.WriteString(out, evaluated.Inspect())
 		io.WriteString(out, "\n")
 	}
 }
 
+// DumpAST parsea el archivo e imprime su AST en JSON, sin evaluarlo.
+func DumpAST(filePath string, out io.Writer) {
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	p := parser.New(lexer.New(string(fileContent)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParserErrors(out, p.Errors())
+		return
+	}
+
+	data, err := ast.MarshalProgramIndent(program)
+	if err != nil {
+		log.Fatalf("Error encoding AST: %v", err)
+	}
+	out.Write(data)
+	io.WriteString(out, "\n")
+}
+
+// DumpCFG parsea el archivo y dibuja su grafo de flujo de control en image,
+// sin evaluarlo.
+func DumpCFG(filePath, image string, out io.Writer) {
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	p := parser.New(lexer.New(string(fileContent)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParserErrors(out, p.Errors())
+		return
+	}
+
+	if err := CreateCFGImage(program, image); err != nil {
+		log.Fatalf("Error generating CFG: %v", err)
+	}
+	log.Printf("CFG saved in: %s", image)
+}
+
+// FormatFiles reescribe cada archivo en el formato canonico. Con check no
+// modifica nada y solo lista los que no estan formateados. Devuelve false si
+// algun archivo no estaba formateado o no se pudo procesar.
+func FormatFiles(paths []string, check bool, out io.Writer) bool {
+	ok := true
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %v\n", path, err)
+			ok = false
+			continue
+		}
+
+		formatted, err := printer.Format(content)
+		if err != nil {
+			fmt.Fprintf(out, "%s:\n", path)
+			printParserErrors(out, strings.Split(err.Error(), "\n"))
+			ok = false
+			continue
+		}
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+
+		if check {
+			fmt.Fprintln(out, path)
+			ok = false
+			continue
+		}
+		if err := os.WriteFile(path, formatted, 0644); err != nil {
+			fmt.Fprintf(out, "%s: %v\n", path, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// LintFiles revisa cada archivo con las reglas de rules (ver
+// lint.ParseConfig) e imprime los diagnosticos. Devuelve false si alguno es
+// de severidad error o si un archivo no se pudo revisar.
+func LintFiles(paths []string, rules string, out io.Writer) bool {
+	config, err := lint.ParseConfig(rules)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return false
+	}
+
+	ok := true
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %v\n", path, err)
+			ok = false
+			continue
+		}
+
+		diagnostics, err := lint.Source(string(content), config)
+		if err != nil {
+			fmt.Fprintf(out, "%s:\n", path)
+			printParserErrors(out, strings.Split(err.Error(), "\n"))
+			ok = false
+			continue
+		}
+		for _, d := range diagnostics {
+			fmt.Fprintf(out, "%s:%s\n", path, d)
+			if d.Severity == lint.Error {
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
 func printParserErrors(out io.Writer, errors []string) {
 	for _, msg := range errors {
 		io.WriteString(out, "\t"+msg+"\n")
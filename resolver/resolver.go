@@ -0,0 +1,250 @@
+package resolver
+
+import (
+	"fmt"
+	"main/ast"
+	"main/token"
+)
+
+// Resolver liga cada ast.Variable con el scope de funcion que la declara y
+// su indice en el, para que el evaluador no tenga que buscar por nombre.
+// Tambien reporta los nombres no definidos y las declaraciones duplicadas.
+//
+// Los scopes son los de funcion, igual que los Environment del evaluador:
+// un bloque de LoverEra no crea uno nuevo, y un nombre declarado adentro se
+// ve desde su enchanted hasta el final de la funcion. Los bloques solo se
+// usan para los duplicados: las dos ramas de un LoverEra pueden declarar el
+// mismo nombre.
+type Resolver struct {
+	errors      []string
+	global      *scope
+	current     *scope
+	predeclared map[string]bool
+}
+
+func New() *Resolver {
+	return &Resolver{
+		errors:      []string{},
+		global:      newScope(nil),
+		predeclared: map[string]bool{"quote": true, "unquote": true},
+	}
+}
+
+// Predeclare marca nombres que existen sin un enchanted, como las funciones
+// predefinidas. No reciben slot: el evaluador los sigue buscando por nombre.
+func (r *Resolver) Predeclare(names ...string) {
+	for _, name := range names {
+		r.predeclared[name] = true
+	}
+}
+
+// Errors devuelve los errores del ultimo Resolve.
+func (r *Resolver) Errors() []string {
+	return r.errors
+}
+
+// Resolve anota las variables del programa. El scope global se conserva
+// entre llamadas, para resolver varios programas sobre el mismo entorno; un
+// programa puede volver a declarar un nombre global de uno anterior.
+func (r *Resolver) Resolve(program *ast.Program) {
+	r.errors = []string{}
+	r.current = r.global
+	r.global.blocks = []map[string]bool{{}}
+	r.hoist(program)
+	for _, s := range program.Statements {
+		r.resolve(s)
+	}
+}
+
+// hoist le da slot a todo lo que se declara en el scope actual antes de
+// recorrerlo, asi una funcion puede usar un nombre que se define despues.
+func (r *Resolver) hoist(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FunctionLiteral, *ast.MacroLiteral:
+			return false
+		case *ast.CallExpression:
+			return !isCall(n, "quote")
+		case *ast.LetStatement:
+			for _, v := range ast.PatternVariables(n.Target()) {
+				r.current.slot(v.Value)
+			}
+		}
+		return true
+	})
+}
+
+func (r *Resolver) resolve(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.LetStatement:
+		if n.Value != nil {
+			r.resolve(n.Value)
+		}
+		r.declare(n.Target())
+	case *ast.BlockStatement:
+		r.current.push()
+		for _, s := range n.Statements {
+			r.resolve(s)
+		}
+		r.current.pop()
+	case *ast.FunctionLiteral:
+		r.current = newScope(r.current)
+		for _, param := range n.Parameters {
+			r.declare(param)
+		}
+		r.hoist(n.Body)
+		r.resolve(n.Body)
+		r.current = r.current.outer
+	case *ast.MacroLiteral:
+		// El cuerpo de un macro corre al expandirlo, con su propio
+		// entorno; sus nombres se buscan por nombre.
+	case *ast.CallExpression:
+		if isCall(n, "quote") {
+			r.resolveUnquotes(n)
+			return
+		}
+		for _, child := range ast.Children(n) {
+			r.resolve(child)
+		}
+	case *ast.Variable:
+		r.reference(n)
+	default:
+		for _, child := range ast.Children(node) {
+			r.resolve(child)
+		}
+	}
+}
+
+// resolveUnquotes solo resuelve los argumentos de los unquote: lo demas
+// dentro de un quote es codigo sin evaluar.
+func (r *Resolver) resolveUnquotes(call *ast.CallExpression) {
+	for _, arg := range call.Arguments {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			unquote, ok := n.(*ast.CallExpression)
+			if !ok || !isCall(unquote, "unquote") {
+				return true
+			}
+			for _, a := range unquote.Arguments {
+				r.resolve(a)
+			}
+			return false
+		})
+	}
+}
+
+func (r *Resolver) declare(pattern ast.Pattern) {
+	for _, v := range ast.PatternVariables(pattern) {
+		if r.current.inOpenBlock(v.Value) {
+			r.errorf(v.Token, "Variable duplicada: %s", v.Value)
+		}
+		v.Resolved, v.Depth, v.Slot = true, 0, r.current.slot(v.Value)
+		r.current.declared[v.Value] = true
+		r.current.blocks[len(r.current.blocks)-1][v.Value] = true
+	}
+}
+
+// reference anota v con el scope mas interno que declara su nombre. Si en
+// ese scope el enchanted todavia no corrio, el evaluador cae a buscarlo por
+// nombre, igual que antes del resolver.
+func (r *Resolver) reference(v *ast.Variable) {
+	depth := 0
+	for s := r.current; s != nil; s = s.outer {
+		if slot, ok := s.slots[v.Value]; ok {
+			v.Resolved, v.Depth, v.Slot = true, depth, slot
+			break
+		}
+		depth++
+	}
+
+	// En su propia funcion un nombre existe desde su enchanted; en las
+	// funciones de afuera existe en todo el scope, porque esta funcion puede
+	// llamarse despues.
+	found := r.current.declared[v.Value] || r.predeclared[v.Value]
+	for s := r.current.outer; !found && s != nil; s = s.outer {
+		_, found = s.slots[v.Value]
+	}
+	if !found {
+		r.errorf(v.Token, "identifier not found: %s", v.Value)
+	}
+}
+
+func (r *Resolver) errorf(tok token.Token, format string, a ...interface{}) {
+	msg := tok.Position() + ": " + fmt.Sprintf(format, a...)
+	r.errors = append(r.errors, msg)
+}
+
+// Snapshot es el scope global en un momento dado.
+type Snapshot struct {
+	slots    map[string]int
+	declared map[string]bool
+}
+
+// Snapshot guarda el scope global, para volver a el con Restore si el
+// programa que se resuelve despues no llega a correr.
+func (r *Resolver) Snapshot() Snapshot {
+	return Snapshot{slots: copyMap(r.global.slots), declared: copyMap(r.global.declared)}
+}
+
+// Restore olvida lo que se declaro despues de s. Los slots que se dieron
+// no se vuelven a usar, porque el entorno puede tenerlos ocupados.
+func (r *Resolver) Restore(s Snapshot) {
+	r.global.slots, r.global.declared = copyMap(s.slots), copyMap(s.declared)
+}
+
+func copyMap[V any](m map[string]V) map[string]V {
+	result := make(map[string]V, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func isCall(call *ast.CallExpression, name string) bool {
+	return call.Function != nil && call.Function.TokenLiteral() == name
+}
+
+// ---------------------------Scopes--------------------------------
+
+type scope struct {
+	outer    *scope
+	slots    map[string]int
+	next     int               // Proximo slot libre; no baja con Restore
+	declared map[string]bool   // Nombres cuyo enchanted ya se resolvio
+	blocks   []map[string]bool // Los mismos, por bloque abierto
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{
+		outer:    outer,
+		slots:    map[string]int{},
+		declared: map[string]bool{},
+		blocks:   []map[string]bool{{}},
+	}
+}
+
+func (s *scope) slot(name string) int {
+	if slot, ok := s.slots[name]; ok {
+		return slot
+	}
+	slot := s.next
+	s.next++
+	s.slots[name] = slot
+	return slot
+}
+
+func (s *scope) inOpenBlock(name string) bool {
+	for _, block := range s.blocks {
+		if block[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *scope) push() {
+	s.blocks = append(s.blocks, map[string]bool{})
+}
+
+func (s *scope) pop() {
+	s.blocks = s.blocks[:len(s.blocks)-1]
+}
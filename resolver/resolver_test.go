@@ -0,0 +1,165 @@
+package resolver
+
+import (
+	"main/ast"
+	"main/lexer"
+	"main/parser"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores al parsear %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+// variables junta todas las apariciones de cada nombre, en orden.
+func variables(program *ast.Program) map[string][]*ast.Variable {
+	found := map[string][]*ast.Variable{}
+	ast.Inspect(program, func(n ast.Node) bool {
+		if v, ok := n.(*ast.Variable); ok {
+			found[v.Value] = append(found[v.Value], v)
+		}
+		return true
+	})
+	return found
+}
+
+func TestResolveSlots(t *testing.T) {
+	input := `
+	enchanted a = 1;
+	enchanted b = 2;
+	enchanted f = isme(x, [y, ...z]) {
+		enchanted c = x + a;
+		isme() { c + b + len(z) };
+	};
+	`
+	program := parse(t, input)
+	r := New()
+	r.Predeclare("len")
+	r.Resolve(program)
+	if len(r.Errors()) != 0 {
+		t.Fatalf("errores inesperados: %v", r.Errors())
+	}
+
+	tests := []struct {
+		name     string
+		index    int
+		resolved bool
+		depth    int
+		slot     int
+	}{
+		{"a", 0, true, 0, 0},
+		{"a", 1, true, 1, 0},
+		{"b", 0, true, 0, 1},
+		{"b", 1, true, 2, 1},
+		{"f", 0, true, 0, 2},
+		{"x", 0, true, 0, 0},
+		{"y", 0, true, 0, 1},
+		{"z", 0, true, 0, 2},
+		{"z", 1, true, 1, 2},
+		{"c", 0, true, 0, 3},
+		{"c", 1, true, 1, 3},
+		{"len", 0, false, 0, 0},
+	}
+
+	found := variables(program)
+	for _, tt := range tests {
+		v := found[tt.name][tt.index]
+		if v.Resolved != tt.resolved || v.Depth != tt.depth || v.Slot != tt.slot {
+			t.Errorf("%s[%d] mal resuelta. Esperaba resolved=%t depth=%d slot=%d, obtuvo resolved=%t depth=%d slot=%d",
+				tt.name, tt.index, tt.resolved, tt.depth, tt.slot, v.Resolved, v.Depth, v.Slot)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"foobar", []string{"1:1: identifier not found: foobar"}},
+		{"x; enchanted x = 1;", []string{"1:1: identifier not found: x"}},
+		{"enchanted x = x;", []string{"1:15: identifier not found: x"}},
+		{"enchanted x = 1; enchanted x = 2;", []string{"1:28: Variable duplicada: x"}},
+		{"isme(a, a) { a }", []string{"1:9: Variable duplicada: a"}},
+		{"enchanted [a, {a}] = b;", []string{"1:22: identifier not found: b", "1:16: Variable duplicada: a"}},
+		{"isme(a) { enchanted a = 1; }", []string{"1:21: Variable duplicada: a"}},
+		// Como en el evaluador, lo que se declara en un bloque sigue visible
+		// en el resto de la funcion.
+		{"LoverEra (SparksFly) { enchanted y = 1 }; y", nil},
+		{"isme() { LoverEra (SparksFly) { enchanted y = 1 }; y }", nil},
+		{"enchanted x = 1; LoverEra (SparksFly) { enchanted x = 2 }", []string{"1:51: Variable duplicada: x"}},
+		// Cada rama es su propio bloque para los duplicados.
+		{"LoverEra (SparksFly) { enchanted y = 1 } RepEra { enchanted y = 2 }", nil},
+		// Una funcion puede usar lo que se define despues en un scope de afuera.
+		{"enchanted f = isme() { g() }; enchanted g = isme() { f() };", nil},
+		{"enchanted f = isme(n) { f(n) };", nil},
+		// Sombrear un nombre de afuera no es duplicarlo.
+		{"enchanted x = 1; isme(x) { x }; isme() { enchanted x = 2; x }", nil},
+		{"len([1]); quote(cualquiera + cosa)", nil},
+		{"quote(unquote(nada))", []string{"1:15: identifier not found: nada"}},
+		{"enchanted m = folklore(a) { nada };", nil},
+	}
+
+	for _, tt := range tests {
+		r := New()
+		r.Predeclare("len")
+		r.Resolve(parse(t, tt.input))
+
+		if len(r.Errors()) != len(tt.expected) {
+			t.Errorf("%q: se esperaban los errores %q, se obtuvieron %q", tt.input, tt.expected, r.Errors())
+			continue
+		}
+		for i, msg := range tt.expected {
+			if r.Errors()[i] != msg {
+				t.Errorf("%q: error erroneo. Esperaba %q, obtuvo %q", tt.input, msg, r.Errors()[i])
+			}
+		}
+	}
+}
+
+func TestResolveKeepsGlobalScope(t *testing.T) {
+	r := New()
+	r.Resolve(parse(t, "enchanted a = 1; enchanted b = 2;"))
+	second := parse(t, "enchanted c = a + b;")
+	r.Resolve(second)
+	if len(r.Errors()) != 0 {
+		t.Fatalf("errores inesperados: %v", r.Errors())
+	}
+
+	found := variables(second)
+	if c := found["c"][0]; c.Slot != 2 {
+		t.Errorf("c deberia usar el slot 2. obtuvo=%d", c.Slot)
+	}
+	if b := found["b"][0]; !b.Resolved || b.Slot != 1 {
+		t.Errorf("b deberia resolverse al slot 1. obtuvo resolved=%t slot=%d", b.Resolved, b.Slot)
+	}
+}
+
+func TestRedeclareAndRestoreGlobals(t *testing.T) {
+	r := New()
+	r.Resolve(parse(t, "enchanted a = 1;"))
+	r.Resolve(parse(t, "enchanted a = 2;"))
+	if len(r.Errors()) != 0 {
+		t.Fatalf("un Run nuevo deberia poder declarar a otra vez: %v", r.Errors())
+	}
+
+	snapshot := r.Snapshot()
+	r.Resolve(parse(t, "enchanted b = 3;"))
+	r.Restore(snapshot)
+	r.Resolve(parse(t, "b"))
+	if len(r.Errors()) != 1 || r.Errors()[0] != "1:1: identifier not found: b" {
+		t.Errorf("b deberia olvidarse con Restore. obtuvo=%v", r.Errors())
+	}
+
+	// El slot que tuvo b no se reusa: el entorno puede tenerlo ocupado.
+	program := parse(t, "enchanted c = a;")
+	r.Resolve(program)
+	if c := variables(program)["c"][0]; c.Slot != 2 {
+		t.Errorf("c deberia usar el slot 2. obtuvo=%d", c.Slot)
+	}
+}
@@ -1,10 +1,19 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // Linea donde empieza el token, desde 1
+	Column  int // Columna donde empieza el token, desde 1
+}
+
+// Position devuelve la ubicacion del token como "linea:columna".
+func (t Token) Position() string {
+	return fmt.Sprintf("%d:%d", t.Line, t.Column)
 }
 
 const (
@@ -25,12 +34,17 @@ const (
 	DIVIDES = "/"
 	COLON   = ":"
 
+	ELLIPSIS = "..."
+
 	LT = "<"
 	GT = ">"
 
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	NULLISH      = "??"
+	OPT_LBRACKET = "?["
+
 	// Delimitadores
 	COMMA     = ","
 	SEMICOLON = ";"
@@ -50,18 +64,24 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	IMPORT   = "IMPORT"
+	MACRO    = "MACRO"
+	NULL     = "NULL"
 
 	STRING = "STRING"
 )
 
 var palabras_reservadas = map[string]TokenType{
-	"isme":      FUNCTION,
-	"enchanted": LET,
-	"SparksFly": TRUE,
-	"BadBlood":  FALSE,
-	"LoverEra":  IF,
-	"RepEra":    ELSE,
-	"hi":        RETURN,
+	"isme":       FUNCTION,
+	"enchanted":  LET,
+	"SparksFly":  TRUE,
+	"BadBlood":   FALSE,
+	"LoverEra":   IF,
+	"RepEra":     ELSE,
+	"hi":         RETURN,
+	"feat":       IMPORT,
+	"folklore":   MACRO,
+	"BlankSpace": NULL,
 }
 
 func CheckIdentificador(identificador string) TokenType {
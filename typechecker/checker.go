@@ -0,0 +1,772 @@
+package typechecker
+
+import (
+	"fmt"
+	"main/ast"
+	"main/object"
+	"main/token"
+	"strings"
+)
+
+// Checker infiere los tipos de un programa antes de evaluarlo y reporta los
+// errores con la posicion del token que los causo.
+type Checker struct {
+	errors  []string
+	types   map[ast.Expression]Type
+	nextVar int
+	global  *scope
+
+	// trail guarda las variables ligadas para poder deshacer una
+	// unificacion fallida en tryUnify.
+	trail []*Var
+	// returns es la pila de tipos de retorno de las funciones abiertas.
+	returns []Type
+
+	// builtins y predeclared son lo que se declaro con Predeclare.
+	builtins    map[string]*object.Builtin
+	predeclared map[string]*scheme
+}
+
+func New() *Checker {
+	return &Checker{
+		errors:      []string{},
+		types:       make(map[ast.Expression]Type),
+		global:      newScope(nil),
+		builtins:    make(map[string]*object.Builtin),
+		predeclared: make(map[string]*scheme),
+	}
+}
+
+// Predeclare declara los nombres que existen sin un enchanted: los builtins,
+// con el tipo de su firma, y las constantes, con el de su valor. Una firma
+// mal escrita es un error del interprete, no del programa, y hace panic.
+func (c *Checker) Predeclare(values map[string]object.Object) {
+	for name, value := range values {
+		builtin, ok := value.(*object.Builtin)
+		if !ok {
+			c.predeclared[name] = &scheme{t: valueType(value)}
+			continue
+		}
+		sc, err := builtinScheme(builtin)
+		if err != nil {
+			panic(fmt.Sprintf("typechecker: %s", err))
+		}
+		c.builtins[name] = builtin
+		c.predeclared[name] = sc
+	}
+}
+
+// Errors devuelve los errores del ultimo Check.
+func (c *Checker) Errors() []string {
+	return c.errors
+}
+
+// TypeOf devuelve el tipo inferido para una expresion ya revisada, o nil si
+// el checker no la visito.
+func (c *Checker) TypeOf(exp ast.Expression) Type {
+	t, ok := c.types[exp]
+	if !ok {
+		return nil
+	}
+	return resolve(t)
+}
+
+// Check revisa el programa. Los nombres globales se conservan entre
+// llamadas, para revisar varios programas sobre el mismo entorno.
+func (c *Checker) Check(program *ast.Program) {
+	c.errors = []string{}
+	c.hoist(program, c.global)
+	c.checkStatements(program.Statements, c.global)
+}
+
+// Snapshot son los nombres globales del Checker en un momento dado.
+type Snapshot struct {
+	names map[string]*scheme
+	trail int
+}
+
+// Snapshot guarda los nombres globales, para volver a ellos con Restore si
+// el programa que se revisa despues no llega a correr.
+func (c *Checker) Snapshot() Snapshot {
+	return Snapshot{names: copyNames(c.global.names), trail: len(c.trail)}
+}
+
+// Restore olvida los nombres declarados despues de s y deshace las
+// variables de tipo que se ligaron desde entonces.
+func (c *Checker) Restore(s Snapshot) {
+	for _, v := range c.trail[s.trail:] {
+		v.instance = nil
+	}
+	c.trail = c.trail[:s.trail]
+	c.global.names = copyNames(s.names)
+}
+
+func copyNames(names map[string]*scheme) map[string]*scheme {
+	result := make(map[string]*scheme, len(names))
+	for name, sc := range names {
+		result[name] = sc
+	}
+	return result
+}
+
+// Declare agrega un nombre global de tipo t, para valores que no vienen de
+// un enchanted.
+func (c *Checker) Declare(name string, t Type) {
+	c.global.names[name] = &scheme{t: t}
+}
+
+// ---------------------------Statements--------------------------------
+
+// checkStatements devuelve el tipo del valor del bloque y si el bloque
+// termina siempre con un `hi`.
+func (c *Checker) checkStatements(stmts []ast.Statement, s *scope) (Type, bool) {
+	var result Type = Null
+	for _, stmt := range stmts {
+		var returns bool
+		result, returns = c.checkStatement(stmt, s)
+		if returns {
+			return result, true
+		}
+	}
+	return result, false
+}
+
+func (c *Checker) checkStatement(stmt ast.Statement, s *scope) (Type, bool) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		c.checkLetStatement(stmt, s)
+		return Null, false
+	case *ast.ReturnStatement:
+		t := c.checkExpression(stmt.ReturnValue, s)
+		if len(c.returns) > 0 {
+			expected := c.returns[len(c.returns)-1]
+			if !c.unify(expected, t) {
+				c.errorf(stmt.Token, "Error de tipos: se retorna %s, pero la funcion retorna %s",
+					resolve(t), resolve(expected))
+			}
+		}
+		return c.freshVar(), true
+	case *ast.ExpressionStatement:
+		if stmt.Expression == nil {
+			return Null, false
+		}
+		return c.checkExpression(stmt.Expression, s), false
+	case *ast.BlockStatement:
+		return c.checkStatements(stmt.Statements, s)
+	}
+	return Null, false
+}
+
+// hoist liga cada nombre que se declara en el scope de node, sin entrar en
+// las funciones, a una variable de tipo nueva antes de revisarlo. Igual que
+// en el resolver, asi una funcion puede llamar a otra definida despues, y
+// dos funciones pueden llamarse entre si.
+func (c *Checker) hoist(node ast.Node, s *scope) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FunctionLiteral, *ast.MacroLiteral:
+			return false
+		case *ast.CallExpression:
+			v, ok := n.Function.(*ast.Variable)
+			return !ok || v.Value != "quote"
+		case *ast.LetStatement:
+			for _, v := range ast.PatternVariables(n.Target()) {
+				s.names[v.Value] = &scheme{t: c.freshVar(), hoisted: true}
+			}
+		}
+		return true
+	})
+}
+
+func (c *Checker) checkLetStatement(stmt *ast.LetStatement, s *scope) {
+	if stmt.Value == nil {
+		return
+	}
+	t := c.checkExpression(stmt.Value, s)
+	if stmt.Pattern != nil {
+		c.bindPattern(stmt.Pattern, t, s, true)
+		return
+	}
+	c.bind(stmt.Name, t, s)
+}
+
+// bind liga name con t generalizado. Si name tenia el tipo provisorio de
+// hoist, los usos anteriores al enchanted tienen que coincidir con t.
+func (c *Checker) bind(name *ast.Variable, t Type, s *scope) {
+	if pre, ok := s.names[name.Value]; ok && pre.hoisted {
+		if !c.unify(pre.t, t) {
+			c.errorf(name.Token, "Error de tipos: %s se usa como %s, pero es %s",
+				name.Value, resolve(pre.t), resolve(t))
+		}
+		// Fuera del scope, para que su propia variable no impida
+		// generalizar t.
+		delete(s.names, name.Value)
+	}
+	s.names[name.Value] = c.generalize(t, s)
+	c.types[name] = t
+}
+
+// bindPattern liga los nombres de un patron con los tipos de las partes de
+// t. Los parametros de funcion no se generalizan.
+func (c *Checker) bindPattern(pattern ast.Pattern, t Type, s *scope, generalize bool) {
+	switch pattern := pattern.(type) {
+	case *ast.Variable:
+		if generalize {
+			c.bind(pattern, t, s)
+		} else {
+			s.names[pattern.Value] = &scheme{t: t}
+			c.types[pattern] = t
+		}
+	case *ast.ArrayPattern:
+		var elem Type = c.freshVar()
+		if prune(t) == Any {
+			elem = Any
+		} else if !c.unify(&Array{Elem: elem}, t) {
+			c.errorf(pattern.Token, "No se puede desestructurar %s como array: %s",
+				resolve(t), pattern.String())
+			elem = Any
+		}
+		for _, element := range pattern.Elements {
+			c.bindPattern(element, elem, s, generalize)
+		}
+		if pattern.Rest != nil {
+			c.bindPattern(pattern.Rest, &Array{Elem: elem}, s, generalize)
+		}
+	case *ast.HashPattern:
+		var value Type = c.freshVar()
+		if prune(t) == Any {
+			value = Any
+		} else if !c.unify(&Hash{Key: String, Value: value}, t) {
+			c.errorf(pattern.Token, "No se puede desestructurar %s como hashMap: %s",
+				resolve(t), pattern.String())
+			value = Any
+		}
+		for _, key := range pattern.Keys {
+			c.bindPattern(key, value, s, generalize)
+		}
+	}
+}
+
+// ---------------------------Expresiones--------------------------------
+
+func (c *Checker) checkExpression(exp ast.Expression, s *scope) Type {
+	if exp == nil {
+		return c.freshVar()
+	}
+	t := c.inferExpression(exp, s)
+	c.types[exp] = t
+	return t
+}
+
+func (c *Checker) inferExpression(exp ast.Expression, s *scope) Type {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.FloatLiteral:
+		return Float
+	case *ast.Boolean:
+		return Bool
+	case *ast.StringLiteral:
+		return String
+	case *ast.NullLiteral:
+		return Null
+	case *ast.Variable:
+		return c.checkVariable(exp, s)
+	case *ast.PrefixExpression:
+		return c.checkPrefixExpression(exp, s)
+	case *ast.InfixExpression:
+		return c.checkInfixExpression(exp, s)
+	case *ast.IfExpression:
+		return c.checkIfExpression(exp, s)
+	case *ast.FunctionLiteral:
+		return c.checkFunctionLiteral(exp, s)
+	case *ast.CallExpression:
+		return c.checkCallExpression(exp, s)
+	case *ast.ArrayLiteral:
+		return c.checkArrayLiteral(exp, s)
+	case *ast.HashLiteral:
+		return c.checkHashLiteral(exp, s)
+	case *ast.IndexExpression:
+		return c.checkIndexExpression(exp, s)
+	case *ast.MacroLiteral:
+		return Any
+	case *ast.ImportExpression:
+		// El checker no lee el modulo: al importarlo el evaluador solo lo
+		// parsea y lo resuelve, asi que sus nombres se usan sin tipo
+		// conocido y sus errores de tipos aparecen al correr.
+		return Any
+	}
+	return Any
+}
+
+func (c *Checker) checkVariable(v *ast.Variable, s *scope) Type {
+	if sc, ok := s.get(v.Value); ok {
+		return c.instantiate(sc)
+	}
+	if sc, ok := c.predeclared[v.Value]; ok {
+		return c.instantiate(sc)
+	}
+	if sc, ok := specialForms[v.Value]; ok {
+		return c.instantiate(sc)
+	}
+	c.errorf(v.Token, "identifier not found: %s", v.Value)
+	return c.freshVar()
+}
+
+func (c *Checker) checkPrefixExpression(pe *ast.PrefixExpression, s *scope) Type {
+	right := prune(c.checkExpression(pe.Right, s))
+	switch pe.Operator {
+	case "!":
+		return Bool
+	case "-":
+		if _, ok := right.(*Var); ok || isNumeric(right) || right == Any {
+			return right
+		}
+	}
+	c.errorf(pe.Token, "Operador desconocido: %s%s", pe.Operator, right)
+	return Any
+}
+
+func (c *Checker) checkInfixExpression(ie *ast.InfixExpression, s *scope) Type {
+	left := prune(c.checkExpression(ie.Left, s))
+	right := prune(c.checkExpression(ie.Right, s))
+
+	switch ie.Operator {
+	case "??":
+		if left == Null {
+			return right
+		}
+		if !c.unify(left, right) {
+			c.errorf(ie.Token, "Error de tipos: %s ?? %s", resolve(left), resolve(right))
+			return Any
+		}
+		return left
+	case "==", "!=":
+		return Bool
+	case "<", ">":
+		if c.numericOperand(left) && c.numericOperand(right) {
+			return Bool
+		}
+		c.errorf(ie.Token, "Error de tipos: %s %s %s", left, ie.Operator, right)
+		return Bool
+	}
+
+	if left == Any || right == Any {
+		return Any
+	}
+	_, leftVar := left.(*Var)
+	_, rightVar := right.(*Var)
+	switch {
+	case leftVar && rightVar:
+		c.unify(left, right)
+		return left
+	case leftVar:
+		return c.arithmeticWithVar(ie, left, right)
+	case rightVar:
+		return c.arithmeticWithVar(ie, right, left)
+	case left == Int && right == Int:
+		return Int
+	case isNumeric(left) && isNumeric(right):
+		return Float
+	case left == String && right == String && ie.Operator == "+":
+		return String
+	case left != right:
+		c.errorf(ie.Token, "Error de tipos: %s %s %s", left, ie.Operator, right)
+	default:
+		c.errorf(ie.Token, "Operador desconocido: %s %s %s", left, ie.Operator, right)
+	}
+	return Any
+}
+
+// arithmeticWithVar resuelve una operacion aritmetica donde un operando aun
+// no tiene tipo: se asume que es del mismo tipo que el operando conocido.
+func (c *Checker) arithmeticWithVar(ie *ast.InfixExpression, v, known Type) Type {
+	if isNumeric(known) || (known == String && ie.Operator == "+") {
+		c.unify(v, known)
+		return known
+	}
+	c.errorf(ie.Token, "Operador desconocido: %s %s %s", v, ie.Operator, known)
+	return Any
+}
+
+func (c *Checker) numericOperand(t Type) bool {
+	if _, ok := t.(*Var); ok {
+		return true
+	}
+	return isNumeric(t) || t == Any
+}
+
+func (c *Checker) checkIfExpression(ie *ast.IfExpression, s *scope) Type {
+	c.checkExpression(ie.Condition, s)
+	consequence, _ := c.checkStatements(ie.Consequence.Statements, s)
+	if ie.Alternative == nil {
+		return consequence
+	}
+	alternative, _ := c.checkStatements(ie.Alternative.Statements, s)
+	// Como en los arrays, ramas de tipos distintos dan Any.
+	return c.join(consequence, alternative)
+}
+
+func (c *Checker) checkFunctionLiteral(fl *ast.FunctionLiteral, s *scope) Type {
+	inner := newScope(s)
+	params := make([]Type, len(fl.Parameters))
+	for i, p := range fl.Parameters {
+		params[i] = c.freshVar()
+		c.bindPattern(p, params[i], inner, false)
+	}
+	c.hoist(fl.Body, inner)
+	ret := c.freshVar()
+	c.returns = append(c.returns, ret)
+	body, returns := c.checkStatements(fl.Body.Statements, inner)
+	c.returns = c.returns[:len(c.returns)-1]
+	if !returns && !c.unify(ret, body) {
+		c.errorf(fl.Token, "Error de tipos: la funcion retorna %s y %s",
+			resolve(ret), resolve(body))
+	}
+	return &Function{Params: params, Return: ret}
+}
+
+func (c *Checker) checkCallExpression(ce *ast.CallExpression, s *scope) Type {
+	callee := prune(c.checkExpression(ce.Function, s))
+	args := make([]Type, len(ce.Arguments))
+	for i, a := range ce.Arguments {
+		args[i] = c.checkExpression(a, s)
+	}
+
+	if builtin, ok := c.builtinCallee(ce.Function, s); ok && !c.checkBuiltinCall(ce, builtin, args) {
+		if fn, ok := callee.(*Function); ok {
+			return fn.Return
+		}
+		return Any
+	}
+
+	switch fn := callee.(type) {
+	case *Function:
+		if fn.Variadic {
+			fixed := len(fn.Params) - 1
+			if len(args) < fixed {
+				c.errorf(ce.Token, "Numero equivocado de argumentos. Son: %d, deberian ser al menos %d",
+					len(args), fixed)
+				return fn.Return
+			}
+			for i, a := range args {
+				c.expectArgument(ce.Arguments[i], fn.Params[min(i, fixed)], a)
+			}
+			return fn.Return
+		}
+		if len(args) != len(fn.Params) {
+			c.errorf(ce.Token, "Numero equivocado de argumentos. Son: %d, deberian ser %d",
+				len(args), len(fn.Params))
+			return fn.Return
+		}
+		for i, a := range args {
+			c.expectArgument(ce.Arguments[i], fn.Params[i], a)
+		}
+		return fn.Return
+	case *Var:
+		ret := c.freshVar()
+		c.unify(fn, &Function{Params: args, Return: ret})
+		return ret
+	}
+	if callee == Any {
+		return Any
+	}
+	c.errorf(ce.Token, "No es una funcion, sino: %s", callee)
+	return Any
+}
+
+// builtinCallee devuelve el builtin al que llama fn, si fn es su nombre y
+// ninguna variable lo oculta.
+func (c *Checker) builtinCallee(fn ast.Expression, s *scope) (*object.Builtin, bool) {
+	v, ok := fn.(*ast.Variable)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := s.get(v.Value); ok {
+		return nil, false
+	}
+	builtin, ok := c.builtins[v.Value]
+	return builtin, ok
+}
+
+// checkBuiltinCall revisa la llamada contra la firma del builtin y reporta
+// los mismos mensajes que daria el evaluador. Los argumentos de tipo
+// desconocido se dejan para el evaluador.
+func (c *Checker) checkBuiltinCall(ce *ast.CallExpression, builtin *object.Builtin, args []Type) bool {
+	if msg := builtin.ArityError(len(args)); msg != "" {
+		c.errorf(ce.Token, "%s", msg)
+		return false
+	}
+	ok := true
+	for i, a := range args {
+		types := objectTypes(resolve(a))
+		if len(types) == 0 {
+			continue
+		}
+		accepted := false
+		names := make([]string, len(types))
+		for j, t := range types {
+			accepted = accepted || builtin.TypeError(i, t) == ""
+			names[j] = string(t)
+		}
+		if !accepted {
+			// Sin correrlo no se sabe cual de los tipos va a tener, asi que
+			// el mensaje los nombra todos: un int puede ser INTEGER o BIGINT.
+			actual := object.ObjectType(strings.Join(names, " o "))
+			c.errorf(ast.TokenOf(ce.Arguments[i]), "%s", builtin.TypeError(i, actual))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// objectTypes devuelve los tipos de objeto que puede tener en ejecucion un
+// valor de tipo t; ninguno si t no se conoce.
+func objectTypes(t Type) []object.ObjectType {
+	switch t := t.(type) {
+	case *Basic:
+		switch t {
+		case Int:
+			return []object.ObjectType{object.INTEGER_OBJ, object.BIGINT_OBJ}
+		case Float:
+			return []object.ObjectType{object.FLOAT_OBJ}
+		case Bool:
+			return []object.ObjectType{object.BOOL_OBJ}
+		case String:
+			return []object.ObjectType{object.STRING_OBJ}
+		case Null:
+			return []object.ObjectType{object.NULL_OBJ}
+		}
+	case *Array:
+		return []object.ObjectType{object.ARRAY_OBJ}
+	case *Hash:
+		return []object.ObjectType{object.HASH_OBJ}
+	case *Function:
+		return []object.ObjectType{object.FUNCTION_OBJ, object.BUILTIN_OBJ}
+	}
+	return nil
+}
+
+func (c *Checker) expectArgument(arg ast.Expression, param, actual Type) {
+	if !c.unify(param, actual) {
+		c.errorf(ast.TokenOf(arg), "Error de tipos: se esperaba %s, se obtuvo %s",
+			resolve(param), resolve(actual))
+	}
+}
+
+func (c *Checker) checkArrayLiteral(al *ast.ArrayLiteral, s *scope) Type {
+	var elem Type = c.freshVar()
+	for _, e := range al.Elements {
+		elem = c.join(elem, c.checkExpression(e, s))
+	}
+	return &Array{Elem: elem}
+}
+
+func (c *Checker) checkHashLiteral(hl *ast.HashLiteral, s *scope) Type {
+	var key Type = c.freshVar()
+	var value Type = c.freshVar()
+	for _, pair := range hl.Pairs {
+		k, v := pair.Key, pair.Value
+		kt := c.checkExpression(k, s)
+		if !c.hashable(kt) {
+			c.errorf(ast.TokenOf(k), "No se puede usar este tipo para llave de hashMap: %s", resolve(kt))
+		}
+		key = c.join(key, kt)
+		value = c.join(value, c.checkExpression(v, s))
+	}
+	return &Hash{Key: key, Value: value}
+}
+
+func (c *Checker) hashable(t Type) bool {
+	switch t := prune(t).(type) {
+	case *Var:
+		return true
+	case *Basic:
+		return t == Int || t == String || t == Bool || t == Any
+	}
+	return false
+}
+
+func (c *Checker) checkIndexExpression(ie *ast.IndexExpression, s *scope) Type {
+	left := prune(c.checkExpression(ie.Left, s))
+	index := c.checkExpression(ie.Index, s)
+	switch left := left.(type) {
+	case *Array:
+		if !c.unify(Int, index) {
+			c.errorf(ie.Token, "Error de tipos: indice de array %s", resolve(index))
+		}
+		return left.Elem
+	case *Hash:
+		if !c.unify(left.Key, index) {
+			c.errorf(ie.Token, "Error de tipos: llave %s en hashMap %s", resolve(index), resolve(left))
+		}
+		return left.Value
+	case *Var:
+		return c.freshVar()
+	}
+	if left == Any {
+		return Any
+	}
+	if left == Null && (ie.Optional || optionalChain(ie.Left)) {
+		return Null
+	}
+	c.errorf(ie.Token, "index operator not supported: %s", left)
+	return Any
+}
+
+// optionalChain dice si e es una cadena de indices con algun ?[, que en
+// ejecucion corta el resto de la cadena cuando encuentra null.
+func optionalChain(e ast.Expression) bool {
+	for {
+		ie, ok := e.(*ast.IndexExpression)
+		if !ok {
+			return false
+		}
+		if ie.Optional {
+			return true
+		}
+		e = ie.Left
+	}
+}
+
+// ---------------------------Unificacion--------------------------------
+
+func (c *Checker) freshVar() *Var {
+	c.nextVar++
+	return &Var{ID: c.nextVar}
+}
+
+func (c *Checker) unify(a, b Type) bool {
+	a, b = prune(a), prune(b)
+	if a == b {
+		return true
+	}
+	if va, ok := a.(*Var); ok {
+		return c.bindVar(va, b)
+	}
+	if vb, ok := b.(*Var); ok {
+		return c.bindVar(vb, a)
+	}
+	// null es un valor valido para cualquier tipo, como lo devuelve un
+	// LoverEra sin RepEra o un indice fuera de rango.
+	if a == Any || b == Any || a == Null || b == Null {
+		return true
+	}
+	switch a := a.(type) {
+	case *Array:
+		if b, ok := b.(*Array); ok {
+			return c.unify(a.Elem, b.Elem)
+		}
+	case *Hash:
+		if b, ok := b.(*Hash); ok {
+			return c.unify(a.Key, b.Key) && c.unify(a.Value, b.Value)
+		}
+	case *Function:
+		b, ok := b.(*Function)
+		if !ok || len(a.Params) != len(b.Params) || a.Variadic != b.Variadic {
+			return false
+		}
+		for i := range a.Params {
+			if !c.unify(a.Params[i], b.Params[i]) {
+				return false
+			}
+		}
+		return c.unify(a.Return, b.Return)
+	}
+	return false
+}
+
+func (c *Checker) bindVar(v *Var, t Type) bool {
+	if t == Null || t == Any {
+		return true
+	}
+	if occursIn(v, t) {
+		return false
+	}
+	v.instance = t
+	c.trail = append(c.trail, v)
+	return true
+}
+
+// tryUnify unifica sin dejar rastro si los tipos no son compatibles.
+func (c *Checker) tryUnify(a, b Type) bool {
+	mark := len(c.trail)
+	if c.unify(a, b) {
+		return true
+	}
+	for _, v := range c.trail[mark:] {
+		v.instance = nil
+	}
+	c.trail = c.trail[:mark]
+	return false
+}
+
+// join combina los tipos de los elementos de una coleccion; si son
+// distintos la coleccion es heterogenea y sus elementos son `any`.
+func (c *Checker) join(a, b Type) Type {
+	if c.tryUnify(a, b) {
+		return a
+	}
+	return Any
+}
+
+func (c *Checker) generalize(t Type, s *scope) *scheme {
+	envVars := make(map[*Var]bool)
+	s.freeVars(envVars)
+	typeVars := make(map[*Var]bool)
+	collectFreeVars(t, map[*Var]bool{}, typeVars)
+	sc := &scheme{t: t}
+	for v := range typeVars {
+		if !envVars[v] {
+			sc.vars = append(sc.vars, v)
+		}
+	}
+	return sc
+}
+
+func (c *Checker) instantiate(sc *scheme) Type {
+	if len(sc.vars) == 0 {
+		return sc.t
+	}
+	mapping := make(map[*Var]Type)
+	for _, v := range sc.vars {
+		mapping[v] = c.freshVar()
+	}
+	return substitute(sc.t, mapping)
+}
+
+func substitute(t Type, mapping map[*Var]Type) Type {
+	switch t := prune(t).(type) {
+	case *Var:
+		if r, ok := mapping[t]; ok {
+			return r
+		}
+		return t
+	case *Array:
+		return &Array{Elem: substitute(t.Elem, mapping)}
+	case *Hash:
+		return &Hash{Key: substitute(t.Key, mapping), Value: substitute(t.Value, mapping)}
+	case *Function:
+		params := make([]Type, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = substitute(p, mapping)
+		}
+		return &Function{Params: params, Return: substitute(t.Return, mapping), Variadic: t.Variadic}
+	default:
+		return t
+	}
+}
+
+// ---------------------------Helper Functions--------------------------------
+
+func (c *Checker) errorf(tok token.Token, format string, a ...interface{}) {
+	msg := tok.Position() + ": " + fmt.Sprintf(format, a...)
+	c.errors = append(c.errors, msg)
+}
+
+// specialForms son quote y unquote: el evaluador los trata aparte, asi que
+// no son builtins con firma.
+var specialForms = map[string]*scheme{
+	"quote":   {t: &Function{Params: []Type{Any}, Return: Any}},
+	"unquote": {t: &Function{Params: []Type{Any}, Return: Any}},
+}
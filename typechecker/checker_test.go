@@ -0,0 +1,234 @@
+package typechecker
+
+import (
+	"main/ast"
+	"main/evaluator"
+	"main/lexer"
+	"main/object"
+	"main/parser"
+	"strings"
+	"testing"
+)
+
+func testCheck(t *testing.T, input string) (*Checker, *ast.Program) {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("errores del parser: %v", p.Errors())
+	}
+	c := New()
+	c.Predeclare(evaluator.Predeclared())
+	c.Check(program)
+	return c, program
+}
+
+func lastExpression(t *testing.T, program *ast.Program) ast.Expression {
+	stmt, ok := program.Statements[len(program.Statements)-1].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("el ultimo statement no es ast.ExpressionStatement. Es: %T",
+			program.Statements[len(program.Statements)-1])
+	}
+	return stmt.Expression
+}
+
+func TestInferredTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5", "int"},
+		{"5.5", "float"},
+		{"SparksFly", "bool"},
+		{`"hola"`, "string"},
+		{"5 + 5 * 2", "int"},
+		{"5 + 2.5", "float"},
+		{`"a" + "b"`, "string"},
+		{"1 < 2", "bool"},
+		{"!5", "bool"},
+		{"-2.5", "float"},
+		{"enchanted x = 10; x", "int"},
+		{"[1, 2, 3]", "[int]"},
+		{`[1, "a"]`, "[any]"},
+		{`{"uno": 1, "dos": 2}`, "{string: int}"},
+		{`{"uno": 1}["uno"]`, "int"},
+		{"[1, 2][0]", "int"},
+		{"isme(x) { x + 1 }", "isme(int) int"},
+		{"enchanted id = isme(x) { x }; id(5)", "int"},
+		{`enchanted id = isme(x) { x }; id(5); id("a")`, "string"},
+		{"len([1, 2])", "int"},
+		{"debut([1.5, 2.5])", "float"},
+		{"billboard([1], 2)", "[int]"},
+		{"input()", "string"},
+		{`map([1, 2], isme(x) { "a" })`, "[string]"},
+		{"filter([1.5], isme(x) { x > 1 })", "[float]"},
+		{"reduce([1, 2], isme(acc, x) { acc + x }, 0)", "int"},
+		{`sort(["b", "a"])`, "[string]"},
+		{`keys({"a": 1})`, "[string]"},
+		{`values({"a": 1.5})`, "[float]"},
+		{`merge({"a": 1}, {"b": 2})`, "{string: int}"},
+		{"LoverEra (SparksFly) { 1 } RepEra { 2 }", "int"},
+		{`LoverEra (SparksFly) { "uno" } RepEra { 1 }`, "any"},
+		{"enchanted later = isme() { helper() + 1 }; enchanted helper = isme() { 41 }; later()", "int"},
+		{`
+enchanted even = isme(n) { LoverEra (n == 0) { SparksFly } RepEra { odd(n - 1) } };
+enchanted odd = isme(n) { LoverEra (n == 0) { BadBlood } RepEra { even(n - 1) } };
+even`, "isme(int) bool"},
+		{"isme() { enchanted f = isme() { g() }; enchanted g = isme() { 1.5 }; f() }", "isme() float"},
+		{"BlankSpace", "null"},
+		{"enchanted [a, ...rest] = [1, 2]; rest", "[int]"},
+		{`enchanted {name} = {"name": "t"}; name`, "string"},
+		{"isme([a, b]) { a + b + 1 }", "isme([int]) int"},
+		{`isme({x}) { x + 1.5 }`, "isme({string: float}) float"},
+		{"BlankSpace ?? 5", "int"},
+		{"[1][3] ?? 5", "int"},
+		{`{"a": 1.5}?["a"]`, "float"},
+		{`BlankSpace?["a"]`, "null"},
+		{`BlankSpace?["a"]["b"][0]`, "null"},
+		{"isme(f) { f(1) }", "isme(isme(int) t3) t3"},
+		{`
+enchanted fibonacci = isme(x) {
+    LoverEra (x == 0) {
+        hi 0;
+    } RepEra {
+        LoverEra (x == 1) {
+            hi 1;
+        } RepEra {
+            fibonacci(x - 1) + fibonacci(x - 2);
+        }
+    }
+};
+fibonacci`, "isme(int) int"},
+	}
+
+	for _, tt := range tests {
+		c, program := testCheck(t, tt.input)
+		if len(c.Errors()) != 0 {
+			t.Errorf("%q: errores inesperados: %v", tt.input, c.Errors())
+			continue
+		}
+		got := c.TypeOf(lastExpression(t, program))
+		if got == nil {
+			t.Errorf("%q: no se infirio ningun tipo", tt.input)
+			continue
+		}
+		if got.String() != tt.expected {
+			t.Errorf("%q: tipo erroneo. Esperaba %s, obtuvo %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5 + SparksFly", "1:3: Error de tipos: int + bool"},
+		{"SparksFly + BadBlood", "1:11: Operador desconocido: bool + bool"},
+		{`"a" - "b"`, "1:5: Operador desconocido: string - string"},
+		{"-SparksFly", "1:1: Operador desconocido: -bool"},
+		{"foobar", "1:1: identifier not found: foobar"},
+		{"enchanted x = 5;\nx(1)", "2:2: No es una funcion, sino: int"},
+		{"enchanted f = isme(a, b) { a + b };\nf(1)", "2:2: Numero equivocado de argumentos. Son: 1, deberian ser 2"},
+		{"enchanted f = isme(a) { a * 2 };\nf(\"x\")", "2:3: Error de tipos: se esperaba int, se obtuvo string"},
+		{"[1, 2][\"a\"]", "1:7: Error de tipos: indice de array string"},
+		{"5[0]", "1:2: index operator not supported: int"},
+		{"BlankSpace[0]", "1:11: index operator not supported: null"},
+		{`1 ?? "a"`, "1:3: Error de tipos: int ?? string"},
+		{"enchanted [a] = 5;", "1:11: No se puede desestructurar int como array: [a]"},
+		{`enchanted {a} = {1: 2};`, "1:11: No se puede desestructurar {int: int} como hashMap: {a}"},
+		{"{[1]: 2}", "1:2: No se puede usar este tipo para llave de hashMap: [int]"},
+		{`enchanted f = isme() { x + 1 }; enchanted x = "a"`, "1:43: Error de tipos: x se usa como int, pero es string"},
+		{"isme(x) { LoverEra (x) { hi 1; } hi \"a\"; }", "1:34: Error de tipos: se retorna string, pero la funcion retorna int"},
+		{"billboard([1])", "1:10: Numero equivocado de argumentos para `billboard`. Son: 1, deberian ser 2"},
+		{"rest(5)", "1:6: Tipo sin soporte para `rest`: el argumento arr deberia ser ARRAY, no INTEGER o BIGINT"},
+		{"len(9223372036854775807 + 1)", "1:25: Tipo sin soporte para `len`: el argumento valor deberia ser STRING o ARRAY, no INTEGER o BIGINT"},
+		{"len(2.5)", "1:5: Tipo sin soporte para `len`: el argumento valor deberia ser STRING o ARRAY, no FLOAT"},
+		{`map(["a"], isme(x) { x * 2 })`, "1:12: Error de tipos: se esperaba isme(string) t2, se obtuvo isme(int) int"},
+		{`has({"a": 1}, 1)`, "1:15: Error de tipos: se esperaba string, se obtuvo int"},
+		{"map([1], 2)", "1:10: Tipo sin soporte para `map`: el argumento fn deberia ser FUNCTION o BUILTIN, no INTEGER o BIGINT"},
+		{"len(isme() { 1 })", "1:5: Tipo sin soporte para `len`: el argumento valor deberia ser STRING o ARRAY, no FUNCTION o BUILTIN"},
+	}
+
+	for _, tt := range tests {
+		c, _ := testCheck(t, tt.input)
+		errors := c.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%q: se esperaba un error de tipos", tt.input)
+			continue
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("%q: error erroneo. Esperaba %q, obtuvo %q", tt.input, tt.expected, errors[0])
+		}
+	}
+}
+
+func TestBuiltinCallsWithUnknownTypes(t *testing.T) {
+	inputs := []string{
+		"isme(x) { len(x) }",
+		"enchanted len = isme(a, b) { a + b }; len(1, 2)",
+		"isme(rest) { rest(1) }",
+		"SpeakNow()",
+		"SpeakNow(1, \"a\", [1])",
+	}
+
+	for _, input := range inputs {
+		c, _ := testCheck(t, input)
+		if len(c.Errors()) != 0 {
+			t.Errorf("%q: errores inesperados: %v", input, c.Errors())
+		}
+	}
+}
+
+func TestCheckSamplePrograms(t *testing.T) {
+	inputs := []string{
+		"enchanted x = 10\nenchanted decimal = 5.5\nenchanted suma = x + decimal\nSpeakNow(suma)\nSpeakNow(\"Holaaa\")",
+		"enchanted x = 10\nLoverEra (x < 100) {\n SpeakNow(SparksFly)\n} RepEra {\n SpeakNow(3)\n}",
+	}
+	for _, input := range inputs {
+		c, _ := testCheck(t, input)
+		if len(c.Errors()) != 0 {
+			t.Errorf("errores inesperados: %s", strings.Join(c.Errors(), "; "))
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"int", "int"},
+		{"[string]", "[string]"},
+		{"{k: v}", "{t0: t1}"},
+		{"isme() float", "isme() float"},
+		{"isme([a], isme(b, a) b, b) b", "isme([t0], isme(t1, t0) t1, t1) t1"},
+		{"isme", `"isme": tipo desconocido "isme"`},
+		{"isme(int", `"isme(int": se esperaba ")" en la posicion 8`},
+		{"[int] int", `"[int] int": sobra "int"`},
+		{"{int}", `"{int}": se esperaba ":" en la posicion 4`},
+	}
+
+	for _, tt := range tests {
+		sc, err := parseType(tt.input)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = sc.t.String()
+		}
+		if got != tt.expected {
+			t.Errorf("parseType(%q): esperaba %q, obtuvo %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestBuiltinSchemeChecksArity(t *testing.T) {
+	builtin := &object.Builtin{Name: "f", Signature: object.Signature{
+		Params: []object.Param{{Name: "x"}},
+		Type:   "isme(int, int) int",
+	}}
+	if _, err := builtinScheme(builtin); err == nil {
+		t.Errorf("una firma con mas argumentos que Params deberia ser un error")
+	}
+}
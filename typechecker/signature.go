@@ -0,0 +1,160 @@
+package typechecker
+
+import (
+	"fmt"
+	"main/object"
+	"strings"
+	"unicode"
+)
+
+// builtinScheme arma el tipo de un builtin a partir de su firma: lee
+// Signature.Type y toma de Params cuantos argumentos recibe y si es variadico.
+func builtinScheme(builtin *object.Builtin) (*scheme, error) {
+	signature := builtin.Signature
+	if signature.Type == "" {
+		params := make([]Type, len(signature.Params))
+		for i := range params {
+			params[i] = Any
+		}
+		return &scheme{t: &Function{Params: params, Return: Any, Variadic: signature.Variadic}}, nil
+	}
+
+	sc, err := parseType(signature.Type)
+	if err != nil {
+		return nil, fmt.Errorf("firma de `%s`: %s", builtin.Name, err)
+	}
+	fn, ok := sc.t.(*Function)
+	if !ok || len(fn.Params) != len(signature.Params) {
+		return nil, fmt.Errorf("firma de `%s`: %q deberia ser una funcion de %d argumentos",
+			builtin.Name, signature.Type, len(signature.Params))
+	}
+	fn.Variadic = signature.Variadic
+	return sc, nil
+}
+
+// valueType es el tipo de una constante predefinida, como PI.
+func valueType(value object.Object) Type {
+	switch value.Type() {
+	case object.INTEGER_OBJ, object.BIGINT_OBJ:
+		return Int
+	case object.FLOAT_OBJ:
+		return Float
+	case object.STRING_OBJ:
+		return String
+	case object.BOOL_OBJ:
+		return Bool
+	case object.NULL_OBJ:
+		return Null
+	}
+	return Any
+}
+
+// parseType lee un tipo con la sintaxis con que se imprimen: int, float,
+// bool, string, null, any, [t], {k: v} e isme(a, b) r. Una letra sola es una
+// variable de tipo, la misma en toda la firma.
+func parseType(src string) (*scheme, error) {
+	p := &typeParser{src: src, vars: map[string]*Var{}}
+	t := p.parseType()
+	p.skipSpaces()
+	if p.err == nil && p.pos < len(p.src) {
+		p.fail("sobra %q", p.src[p.pos:])
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &scheme{vars: p.order, t: t}, nil
+}
+
+type typeParser struct {
+	src   string
+	pos   int
+	vars  map[string]*Var
+	order []*Var // Las variables en el orden en que aparecen
+	err   error
+}
+
+func (p *typeParser) parseType() Type {
+	if p.err != nil {
+		return Any
+	}
+	switch {
+	case p.accept("["):
+		elem := p.parseType()
+		p.expect("]")
+		return &Array{Elem: elem}
+	case p.accept("{"):
+		key := p.parseType()
+		p.expect(":")
+		value := p.parseType()
+		p.expect("}")
+		return &Hash{Key: key, Value: value}
+	case p.accept("isme("):
+		fn := &Function{}
+		if !p.accept(")") {
+			for p.err == nil {
+				fn.Params = append(fn.Params, p.parseType())
+				if !p.accept(",") {
+					break
+				}
+			}
+			p.expect(")")
+		}
+		fn.Return = p.parseType()
+		return fn
+	}
+
+	name := p.word()
+	for _, basic := range []*Basic{Int, Float, Bool, String, Null, Any} {
+		if name == basic.Name {
+			return basic
+		}
+	}
+	if len(name) == 1 {
+		v, ok := p.vars[name]
+		if !ok {
+			v = &Var{ID: len(p.order)}
+			p.vars[name] = v
+			p.order = append(p.order, v)
+		}
+		return v
+	}
+	p.fail("tipo desconocido %q", name)
+	return Any
+}
+
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// accept consume s si es lo que sigue.
+func (p *typeParser) accept(s string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.src[p.pos:], s) {
+		return false
+	}
+	p.pos += len(s)
+	return true
+}
+
+func (p *typeParser) expect(s string) {
+	if p.err == nil && !p.accept(s) {
+		p.fail("se esperaba %q en la posicion %d", s, p.pos)
+	}
+}
+
+func (p *typeParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) && unicode.IsLetter(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *typeParser) fail(format string, a ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%q: %s", p.src, fmt.Sprintf(format, a...))
+	}
+}
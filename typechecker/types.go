@@ -0,0 +1,189 @@
+package typechecker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Type es cualquier tipo que el checker puede inferir.
+type Type interface {
+	String() string
+}
+
+// Basic representa los tipos primitivos del lenguaje.
+type Basic struct {
+	Name string
+}
+
+func (b *Basic) String() string { return b.Name }
+
+var (
+	Int    = &Basic{Name: "int"}
+	Float  = &Basic{Name: "float"}
+	Bool   = &Basic{Name: "bool"}
+	String = &Basic{Name: "string"}
+	Null   = &Basic{Name: "null"}
+	// Any acepta cualquier valor; lo usan los builtins polimorficos como
+	// `len` o `SpeakNow` y las colecciones con elementos heterogeneos.
+	Any = &Basic{Name: "any"}
+)
+
+type Array struct {
+	Elem Type
+}
+
+func (a *Array) String() string { return "[" + a.Elem.String() + "]" }
+
+type Hash struct {
+	Key   Type
+	Value Type
+}
+
+func (h *Hash) String() string {
+	return "{" + h.Key.String() + ": " + h.Value.String() + "}"
+}
+
+type Function struct {
+	Params   []Type
+	Return   Type
+	Variadic bool // El ultimo parametro se repite, como en SpeakNow
+}
+
+func (f *Function) String() string {
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range f.Params {
+		params = append(params, p.String())
+	}
+	out.WriteString("isme(")
+	out.WriteString(strings.Join(params, ", "))
+	if f.Variadic {
+		out.WriteString("...")
+	}
+	out.WriteString(") ")
+	out.WriteString(f.Return.String())
+	return out.String()
+}
+
+// Var es una variable de tipo que la unificacion va resolviendo.
+type Var struct {
+	ID       int
+	instance Type
+}
+
+func (v *Var) String() string {
+	if v.instance != nil {
+		return v.instance.String()
+	}
+	return fmt.Sprintf("t%d", v.ID)
+}
+
+// prune sigue las variables ya resueltas hasta llegar a un tipo concreto o
+// a una variable libre.
+func prune(t Type) Type {
+	if v, ok := t.(*Var); ok && v.instance != nil {
+		return prune(v.instance)
+	}
+	return t
+}
+
+// resolve reemplaza recursivamente todas las variables resueltas.
+func resolve(t Type) Type {
+	switch t := prune(t).(type) {
+	case *Array:
+		return &Array{Elem: resolve(t.Elem)}
+	case *Hash:
+		return &Hash{Key: resolve(t.Key), Value: resolve(t.Value)}
+	case *Function:
+		params := make([]Type, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = resolve(p)
+		}
+		return &Function{Params: params, Return: resolve(t.Return), Variadic: t.Variadic}
+	default:
+		return t
+	}
+}
+
+func occursIn(v *Var, t Type) bool {
+	switch t := prune(t).(type) {
+	case *Var:
+		return t == v
+	case *Array:
+		return occursIn(v, t.Elem)
+	case *Hash:
+		return occursIn(v, t.Key) || occursIn(v, t.Value)
+	case *Function:
+		for _, p := range t.Params {
+			if occursIn(v, p) {
+				return true
+			}
+		}
+		return occursIn(v, t.Return)
+	}
+	return false
+}
+
+func isNumeric(t Type) bool {
+	return t == Int || t == Float
+}
+
+// scheme es un tipo generalizado: las variables de vars se reemplazan por
+// variables nuevas cada vez que se usa el nombre, como en fibonacci.
+type scheme struct {
+	vars []*Var
+	t    Type
+	// hoisted marca el tipo provisorio de un nombre que se usa antes de su
+	// enchanted, ver hoist.
+	hoisted bool
+}
+
+type scope struct {
+	names map[string]*scheme
+	outer *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{names: make(map[string]*scheme), outer: outer}
+}
+
+func (s *scope) get(name string) (*scheme, bool) {
+	sc, ok := s.names[name]
+	if !ok && s.outer != nil {
+		return s.outer.get(name)
+	}
+	return sc, ok
+}
+
+func (s *scope) freeVars(into map[*Var]bool) {
+	for _, sc := range s.names {
+		bound := make(map[*Var]bool)
+		for _, v := range sc.vars {
+			bound[v] = true
+		}
+		collectFreeVars(sc.t, bound, into)
+	}
+	if s.outer != nil {
+		s.outer.freeVars(into)
+	}
+}
+
+func collectFreeVars(t Type, bound, into map[*Var]bool) {
+	switch t := prune(t).(type) {
+	case *Var:
+		if !bound[t] {
+			into[t] = true
+		}
+	case *Array:
+		collectFreeVars(t.Elem, bound, into)
+	case *Hash:
+		collectFreeVars(t.Key, bound, into)
+		collectFreeVars(t.Value, bound, into)
+	case *Function:
+		for _, p := range t.Params {
+			collectFreeVars(p, bound, into)
+		}
+		collectFreeVars(t.Return, bound, into)
+	}
+}